@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+// probeAgent checks targets from a remote location and reports the results to
+// the central sup web server, which decides whether a target is down
+type probeAgent struct {
+	centralURL   string
+	token        string
+	simulateDown bool
+	client       *http.Client
+}
+
+func newProbeAgent(centralURL, token string, simulateDown bool) *probeAgent {
+	if centralURL == "" || token == "" {
+		log.Fatalln("probe agents need --central_url and --probe_token")
+	}
+	if location == common.DefaultLocation {
+		log.Fatalln("probe agents need a --location other than " + common.DefaultLocation)
+	}
+	return &probeAgent{
+		centralURL:   strings.TrimSuffix(centralURL, "/"),
+		token:        token,
+		simulateDown: simulateDown,
+		client:       &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+// Targets fetches the target list from the central server. It returns nil if
+// that fails, so the scheduler keeps the targets it already has.
+func (a *probeAgent) Targets() []common.TargetType {
+	var targets []common.TargetType
+	if err := a.do("GET", "/probe/targets", nil, &targets); err != nil {
+		log.Printf("could not load targets from central: %s\n", err)
+		return nil
+	}
+	if targets == nil {
+		targets = []common.TargetType{}
+	}
+	return targets
+}
+
+func (a *probeAgent) Check(target common.TargetType) {
+	result := confirmedProbe(target, a.simulateDown)
+	if !result.Up {
+		log.Printf("%s is down from %s. Status is %d\n", target.Name, location, result.StatusCode)
+	}
+
+	if err := a.do("POST", "/probe/results", result, nil); err != nil {
+		log.Printf("could not report %s to central: %s\n", target.Name, err)
+	}
+}
+
+func (a *probeAgent) do(method, path string, body, out interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, a.centralURL+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SupProbe")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("central returned %s", resp.Status)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -1,5 +1,6 @@
 #!/bin/bash
 
+# needs Go 1.21 or newer, for the min and max builtins, among others
 #GO15VENDOREXPERIMENT=1
 #go-bindata -pkg webserver -prefix webserver/ -o webserver/static.go webserver/templates/
 go generate $(go list ./... | grep -v /vendor/)
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// the audit log is a sorted set scored by a sequence number, so entries keep
+// the order they were written in. Nothing ever removes entries.
+var redisAuditKey = "sup:audit"
+var redisAuditSeqKey = "sup:audit:seq"
+
+// AuditEventType records one change someone made
+type AuditEventType struct {
+	Seq          int64
+	At           time.Time
+	Actor        string
+	IP           string `json:",omitempty"`
+	ForwardedFor string `json:",omitempty"` // X-Forwarded-For, as the client sent it
+	Action       string // e.g. config.update, target.delete, alerting.disable
+	Detail       string `json:",omitempty"`
+	Diff         string `json:",omitempty"` // for config changes, what changed
+}
+
+// AuditFilterType picks which audit events GetAuditLog returns. Empty fields
+// match everything.
+type AuditFilterType struct {
+	Actor  string
+	Action string // an exact action, or a prefix like "user." or "target"
+	Since  time.Time
+	Until  time.Time
+}
+
+// AuditPageType is one page of audit events, newest first. Pass Next as
+// `before` to get the following page; it is 0 on the last page. A filtered
+// page can hold fewer than limit events, or none, and still have a Next, when
+// the search stopped after maxAuditScan entries.
+type AuditPageType struct {
+	Events []AuditEventType
+	Next   int64
+}
+
+func (f AuditFilterType) matches(e AuditEventType) bool {
+	if f.Actor != "" && e.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action && !strings.HasPrefix(e.Action, strings.TrimSuffix(f.Action, ".")+".") {
+		return false
+	}
+	if !f.Until.IsZero() && e.At.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Audit appends an event to the audit log
+func Audit(e AuditEventType) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	e.Seq, err = redis.Int64(c.Do("INCR", redisAuditSeqKey))
+	check(err)
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+
+	data, err := json.Marshal(e)
+	check(err)
+	_, err = c.Do("ZADD", redisAuditKey, e.Seq, data)
+	check(err)
+}
+
+// maxAuditScan is how many entries one GetAuditLog call looks through before
+// giving up on filling the page, so a rare actor or action doesn't read the
+// whole log on every request
+const maxAuditScan = 2000
+
+// GetAuditLog returns up to limit events that match the filter, starting
+// before the event with sequence number before (or the newest if 0)
+func GetAuditLog(filter AuditFilterType, before int64, limit int) AuditPageType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	page := AuditPageType{Events: []AuditEventType{}}
+	max := "+inf"
+	if before > 0 {
+		max = "(" + strconv.FormatInt(before, 10)
+	}
+
+	const chunk = 200
+	scanned, last := 0, int64(0)
+	for {
+		values, err := redis.Strings(c.Do("ZREVRANGEBYSCORE", redisAuditKey, max, "-inf", "LIMIT", 0, chunk))
+		check(err)
+
+		for _, data := range values {
+			var e AuditEventType
+			if json.Unmarshal([]byte(data), &e) != nil {
+				continue
+			}
+			max = "(" + strconv.FormatInt(e.Seq, 10)
+			scanned, last = scanned+1, e.Seq
+			if !filter.Since.IsZero() && e.At.Before(filter.Since) {
+				return page
+			}
+			if !filter.matches(e) {
+				continue
+			}
+			page.Events = append(page.Events, e)
+			if len(page.Events) == limit {
+				page.Next = e.Seq
+				return page
+			}
+		}
+		if len(values) < chunk {
+			return page
+		}
+		if scanned >= maxAuditScan {
+			page.Next = last
+			return page
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-	"time"
 
 	"github.com/andybons/hipchat"
 	"github.com/garyburd/redigo/redis"
@@ -31,16 +30,23 @@ type ConfigType struct {
 	TwilioCallFrom   string
 	HipchatAuthToken string
 	HipchatRoom      string
+	Targets          []TargetType
+	HistoryDays      int // how long check results are kept. at least StatusPageDays for targets on the status page
+	ConfigVersions   int // how many old versions of the config are kept
+	StatusPage       StatusPageType
 }
 
 type StatusType struct {
-	Disabled   bool
-	LastStatus int
-	LastRunAt  time.Time
-	NumErrors  int
+	Disabled bool
 }
 
 func HipchatMessage(message string) {
+	HipchatNotice(message, hipchat.ColorRed)
+}
+
+// HipchatNotice posts a message in the given color, e.g. hipchat.ColorGreen for
+// good news
+func HipchatNotice(message, color string) {
 	config := GetConfig()
 	if config.HipchatAuthToken == "" || config.HipchatRoom == "" {
 		return
@@ -52,17 +58,16 @@ func HipchatMessage(message string) {
 		RoomId:        config.HipchatRoom,
 		From:          "SUP",
 		Message:       message,
-		Color:         hipchat.ColorRed,
+		Color:         color,
 		MessageFormat: hipchat.FormatText,
 		Notify:        true,
 	})
 
+	CountNotification(ChannelHipchat, err)
 	check(err)
 }
 
 func GetConfig() ConfigType {
-	var conf ConfigType
-
 	c, err := getRedis()
 	check(err)
 	defer c.Close()
@@ -76,20 +81,70 @@ func GetConfig() ConfigType {
 		}
 	}
 
-	json.Unmarshal(confData, &conf)
-	return conf
+	return readConfig(confData)
 }
 
-func SetConfig(config ConfigType) {
-	json, err := json.Marshal(config)
-	check(err)
+// SetConfig replaces the config, keeping the old one as a version
+func SetConfig(config ConfigType, author string) {
+	check(UpdateConfig(author, func(c *ConfigType) error {
+		*c = config
+		return nil
+	}))
+}
 
+// UpdateConfig applies fn to the stored config and saves it, retrying if
+// someone else saved the config at the same time. If fn returns an error
+// nothing is saved and the error is returned. A new config version is
+// recorded for author if anything changed. Changes are refused with
+// ErrConfigReadOnly if the config is managed in a file.
+func UpdateConfig(author string, fn func(*ConfigType) error) error {
+	return updateConfig(author, "", fn)
+}
+
+// updateConfig is UpdateConfig with a note for the version. It refuses if the
+// config is managed in a file.
+func updateConfig(author, note string, fn func(*ConfigType) error) error {
+	if configFileAuthoritative {
+		return ErrConfigReadOnly
+	}
+	return writeConfig(author, note, fn)
+}
+
+func writeConfig(author, note string, fn func(*ConfigType) error) error {
 	c, err := getRedis()
 	check(err)
 	defer c.Close()
 
-	_, err = c.Do("SET", redisConfigKey, json)
-	check(err)
+	for {
+		_, err = c.Do("WATCH", redisConfigKey, redisConfigVersionsKey)
+		check(err)
+
+		config := GetConfig()
+		before, err := json.Marshal(config)
+		check(err)
+		if err := fn(&config); err != nil {
+			c.Do("UNWATCH")
+			return err
+		}
+
+		data, err := json.Marshal(config)
+		check(err)
+		var versions [][]byte
+		if string(data) != string(before) {
+			versions = newConfigVersions(c, before, config, author, note)
+		}
+		stored, err := json.Marshal(encryptSecrets(config))
+		check(err)
+
+		c.Send("MULTI")
+		c.Send("SET", redisConfigKey, stored)
+		queueConfigVersions(c, versions, config)
+		reply, err := c.Do("EXEC")
+		check(err)
+		if reply != nil {
+			return nil
+		}
+	}
 }
 
 func GetStatus() StatusType {
@@ -0,0 +1,180 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// the file the config is loaded from. empty if the config only lives in redis
+var configFile string
+
+// whether the file is the only way to change the config
+var configFileAuthoritative bool
+
+// ErrConfigReadOnly is returned for changes to a config that is managed in a file
+var ErrConfigReadOnly = errors.New("the config is managed in a file and can't be changed here")
+
+// ${NAME} or ${NAME:-default}. $${ is a literal ${
+var envVarRegexp = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// SetConfigFile sets the file the config is loaded from. If authoritative is
+// set, the config can only be changed by changing the file. Every sup process
+// sharing the config should be given the same file.
+func SetConfigFile(path string, authoritative bool) {
+	configFile = path
+	configFileAuthoritative = path != "" && authoritative
+}
+
+// ConfigFile returns the file the config is loaded from, if any, and whether
+// the file is the only way to change it
+func ConfigFile() (string, bool) {
+	return configFile, configFileAuthoritative
+}
+
+// ReadConfigFile reads a YAML, JSON or TOML config, going by the file's
+// extension. The fields are the same as in the JSON config. ${NAME} in string
+// values is replaced with the environment variable, or with the default in
+// ${NAME:-default} if it isn't set.
+func ReadConfigFile(path string) (ConfigType, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ConfigType{}, err
+	}
+
+	var tree interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		var table map[string]interface{}
+		err = toml.Unmarshal(data, &table)
+		tree = table
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if decoder.Decode(&tree) != nil {
+			// ParseConfig says where the JSON is broken
+			_, err = ParseConfig(data)
+		}
+	default:
+		return ConfigType{}, fmt.Errorf("%s: config files must end in .yaml, .yml, .json or .toml", path)
+	}
+	if err != nil {
+		return ConfigType{}, fmt.Errorf("%s: %s", path, err)
+	}
+
+	var errs ConfigErrors
+	tree = expandEnv(normalizeTree(tree), "", &errs)
+	if len(errs) > 0 {
+		return ConfigType{}, fmt.Errorf("%s: %s", path, errs)
+	}
+
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return ConfigType{}, fmt.Errorf("%s: %s", path, err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return config, fmt.Errorf("%s: %s", path, err)
+	}
+	return config, nil
+}
+
+// normalizeTree turns what the YAML and TOML decoders give into what the JSON
+// decoder would, so it can be checked like a JSON config
+func normalizeTree(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		obj := map[string]interface{}{}
+		for key, value := range v {
+			obj[fmt.Sprint(key)] = normalizeTree(value)
+		}
+		return obj
+	case map[string]interface{}:
+		obj := map[string]interface{}{}
+		for key, value := range v {
+			obj[key] = normalizeTree(value)
+		}
+		return obj
+	case []map[string]interface{}:
+		list := []interface{}{}
+		for _, value := range v {
+			list = append(list, normalizeTree(value))
+		}
+		return list
+	case []interface{}:
+		list := []interface{}{}
+		for _, value := range v {
+			list = append(list, normalizeTree(value))
+		}
+		return list
+	}
+	return v
+}
+
+// expandEnv replaces environment variables in the tree's strings
+func expandEnv(v interface{}, path string, errs *ConfigErrors) interface{} {
+	switch v := v.(type) {
+	case string:
+		return envVarRegexp.ReplaceAllStringFunc(v, func(match string) string {
+			if match == "$${" {
+				return "${"
+			}
+			parts := envVarRegexp.FindStringSubmatch(match)
+			if value, ok := os.LookupEnv(parts[1]); ok {
+				return value
+			}
+			if parts[2] != "" {
+				return parts[3]
+			}
+			errs.add(path, "environment variable %s isn't set", parts[1])
+			return ""
+		})
+	case map[string]interface{}:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v[key] = expandEnv(v[key], fieldPath(path, key), errs)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = expandEnv(v[i], indexPath(path, i), errs)
+		}
+	}
+	return v
+}
+
+// LoadConfigFile makes the config file's contents the config, recorded as a
+// new version if anything changed. It returns what changed, which is empty if
+// nothing did.
+func LoadConfigFile() (string, error) {
+	if configFile == "" {
+		return "", fmt.Errorf("no config file is set")
+	}
+	fileConfig, err := ReadConfigFile(configFile)
+	if err != nil {
+		return "", err
+	}
+
+	var diff string
+	err = writeConfig("file:"+configFile, "loaded from "+configFile, func(config *ConfigType) error {
+		diff = ConfigDiff(*config, fileConfig)
+		*config = fileConfig
+		return nil
+	})
+	return diff, err
+}
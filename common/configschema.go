@@ -0,0 +1,461 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bounds for the numbers in the config
+const (
+	MaxInterval   = 24 * 60 * 60 // seconds
+	MaxTimeout    = 5 * 60       // seconds
+	MaxRetries    = 10
+	MaxRetryDelay = 5 * 60 // seconds
+)
+
+// E.164: a plus, then up to 15 digits with no leading zero
+var phoneRegexp = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// FieldError is a problem with one field of the config. Path looks like
+// Targets[2].Interval.
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ConfigErrors is every problem found in a config
+type ConfigErrors []FieldError
+
+func (e ConfigErrors) Error() string {
+	lines := []string{}
+	for _, f := range e {
+		lines = append(lines, f.Error())
+	}
+	return strings.Join(lines, "; ")
+}
+
+func (e *ConfigErrors) add(path, format string, args ...interface{}) {
+	*e = append(*e, FieldError{path, fmt.Sprintf(format, args...)})
+}
+
+// orNil is nil if there were no errors, so callers can check err != nil
+func (e ConfigErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func fieldPath(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+func indexPath(parent string, i int) string {
+	return fmt.Sprintf("%s[%d]", parent, i)
+}
+
+// ParseConfig reads a config strictly: the JSON must be valid, every field
+// must exist and have the right type, and the values must pass Validate. The
+// error is a ConfigErrors unless the JSON itself is broken.
+func ParseConfig(data []byte) (ConfigType, error) {
+	var config ConfigType
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return config, jsonSyntaxError(data, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return config, ConfigErrors{{"", "there is more after the end of the config object"}}
+	}
+
+	var errs ConfigErrors
+	checkSchema(raw, reflect.TypeOf(config), "", &errs)
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+		return config, errs
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, ConfigErrors{{"", err.Error()}}
+	}
+	return config, config.Validate()
+}
+
+// jsonSyntaxError says where in the text the JSON broke
+func jsonSyntaxError(data []byte, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ConfigErrors{{"", "the config ends too soon. is a } or ] missing?"}}
+	}
+	if e, ok := err.(*json.SyntaxError); ok {
+		before := data[:e.Offset]
+		line := bytes.Count(before, []byte("\n")) + 1
+		column := len(before) - bytes.LastIndexByte(before, '\n')
+		return ConfigErrors{{"", fmt.Sprintf("line %d, column %d: %s", line, column, e.Error())}}
+	}
+	return ConfigErrors{{"", err.Error()}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// checkSchema checks a value decoded into interface{} against the Go type it
+// will be unmarshaled into, so mistakes are reported instead of dropped
+func checkSchema(v interface{}, t reflect.Type, path string, errs *ConfigErrors) {
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface:
+		default:
+			errs.add(path, "can't be null")
+		}
+		return
+	}
+
+	if t == timeType {
+		s, ok := v.(string)
+		if !ok {
+			errs.add(path, "must be a time like \"2006-01-02T15:04:05Z\"")
+		} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+			errs.add(path, "%q isn't a time like \"2006-01-02T15:04:05Z\"", s)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		checkSchema(v, t.Elem(), path, errs)
+
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			errs.add(path, "must be an object, not %s", describeJSON(v))
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			field, ok := fields[key]
+			if !ok {
+				if suggestion := closestField(key, fields); suggestion != "" {
+					errs.add(fieldPath(path, key), "unknown field. did you mean %s?", suggestion)
+				} else {
+					errs.add(fieldPath(path, key), "unknown field")
+				}
+				continue
+			}
+			checkSchema(value, field.Type, fieldPath(path, key), errs)
+		}
+
+	case reflect.Slice, reflect.Array:
+		list, ok := v.([]interface{})
+		if !ok {
+			errs.add(path, "must be a list, not %s", describeJSON(v))
+			return
+		}
+		for i, item := range list {
+			checkSchema(item, t.Elem(), indexPath(path, i), errs)
+		}
+
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			errs.add(path, "must be an object, not %s", describeJSON(v))
+			return
+		}
+		for key, value := range obj {
+			checkSchema(value, t.Elem(), fieldPath(path, key), errs)
+		}
+
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			errs.add(path, "must be a string, not %s", describeJSON(v))
+		}
+
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			errs.add(path, "must be true or false, not %s", describeJSON(v))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := v.(json.Number)
+		if !ok {
+			errs.add(path, "must be a number, not %s", describeJSON(v))
+		} else if _, err := n.Int64(); err != nil {
+			errs.add(path, "must be a whole number, not %s", n)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(json.Number); !ok {
+			errs.add(path, "must be a number, not %s", describeJSON(v))
+		}
+	}
+}
+
+func describeJSON(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case json.Number:
+		return "the number " + v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return "null"
+}
+
+// jsonFields maps the JSON names of a struct's fields to the fields
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// closestField suggests the field that was probably meant: one that differs
+// only in case, or by a couple of letters
+func closestField(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if bestDistance > 2 {
+		return ""
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Validate checks the values in the config make sense
+func (c ConfigType) Validate() error {
+	var errs ConfigErrors
+
+	for i, phone := range c.Phones {
+		if !ValidPhone(phone) {
+			errs.add(indexPath("Phones", i), "%q isn't an E.164 phone number like +15551234567", phone)
+		}
+	}
+	if c.TwilioCallFrom != "" && !ValidPhone(c.TwilioCallFrom) {
+		errs.add("TwilioCallFrom", "%q isn't an E.164 phone number like +15551234567", c.TwilioCallFrom)
+	}
+	if c.URL != "" {
+		checkURL(c.URL, "URL", &errs)
+	}
+	if c.PingFreq != 0 {
+		checkInterval(c.PingFreq, "PingFreq", &errs)
+	}
+	if c.HistoryDays < 0 {
+		errs.add("HistoryDays", "can't be negative")
+	}
+	if c.ConfigVersions < 0 {
+		errs.add("ConfigVersions", "can't be negative")
+	}
+
+	names := map[string]bool{}
+	if c.URL != "" {
+		names[DefaultTargetName] = true
+	}
+	for i, t := range c.Targets {
+		name := t.Name
+		if name == "" {
+			name = t.URL
+		}
+		if names[name] {
+			errs.add(indexPath("Targets", i)+".Name", "there is already a target called %q", name)
+		}
+		names[name] = true
+	}
+	parents := map[string][]string{}
+	for _, t := range c.Targets {
+		name := t.Name
+		if name == "" {
+			name = t.URL
+		}
+		parents[name] = t.Parents
+	}
+	for i, t := range c.Targets {
+		path := indexPath("Targets", i)
+		t.validate(path, &errs)
+		for j, p := range t.Parents {
+			if p == t.Name {
+				errs.add(indexPath(path+".Parents", j), "a target can't be its own parent")
+			} else if !names[p] {
+				errs.add(indexPath(path+".Parents", j), "there is no target called %q", p)
+			}
+		}
+		name := t.Name
+		if name == "" {
+			name = t.URL
+		}
+		if cycle := parentCycle(parents, name); cycle != nil {
+			errs.add(path+".Parents", "parents lead back to this target: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	for i, component := range c.StatusPage.Components {
+		path := indexPath("StatusPage.Components", i)
+		if strings.TrimSpace(component.Name) == "" {
+			errs.add(path+".Name", "is required")
+		}
+		for j, name := range component.Targets {
+			if !names[name] {
+				errs.add(indexPath(path+".Targets", j), "there is no target called %q", name)
+			}
+		}
+	}
+
+	return errs.orNil()
+}
+
+// parentCycle returns the targets that lead from start through its parents back
+// to start, or nil if they don't. A target that is its own parent is reported
+// separately, so it isn't counted.
+func parentCycle(parents map[string][]string, start string) []string {
+	path := []string{}
+	seen := map[string]bool{}
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		path = append(path, name)
+		for _, p := range parents[name] {
+			if p == name {
+				continue
+			}
+			if p == start {
+				path = append(path, start)
+				return true
+			}
+			if !seen[p] {
+				seen[p] = true
+				if visit(p) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(start) {
+		return path
+	}
+	return nil
+}
+
+// Validate checks a single target's values
+func (t TargetType) Validate() error {
+	var errs ConfigErrors
+	t.validate("", &errs)
+	return errs.orNil()
+}
+
+func (t TargetType) validate(path string, errs *ConfigErrors) {
+	checkURL(t.URL, fieldPath(path, "URL"), errs)
+	if t.Interval != 0 {
+		checkInterval(t.Interval, fieldPath(path, "Interval"), errs)
+	}
+	if t.Timeout < 0 || t.Timeout > MaxTimeout {
+		errs.add(fieldPath(path, "Timeout"), "must be between 0 and %d seconds", MaxTimeout)
+	}
+	if t.Retries < 0 || t.Retries > MaxRetries {
+		errs.add(fieldPath(path, "Retries"), "must be between 0 and %d", MaxRetries)
+	}
+	if t.RetryDelay < 0 || t.RetryDelay > MaxRetryDelay {
+		errs.add(fieldPath(path, "RetryDelay"), "must be between 0 and %d seconds", MaxRetryDelay)
+	}
+	if t.Quorum < 0 {
+		errs.add(fieldPath(path, "Quorum"), "can't be negative")
+	}
+	if t.Schedule != "" {
+		if _, err := ParseCron(t.Schedule); err != nil {
+			errs.add(fieldPath(path, "Schedule"), "%s", err)
+		}
+	}
+	if _, err := ParseActiveWindow(t.ActiveHours, t.ActiveDays); err != nil {
+		errs.add(fieldPath(path, "ActiveHours"), "%s", err)
+	}
+	if t.Timezone != "" {
+		if _, err := time.LoadLocation(t.Timezone); err != nil {
+			errs.add(fieldPath(path, "Timezone"), "unknown timezone %q", t.Timezone)
+		}
+	}
+	for i, slo := range t.SLOs {
+		if slo.Objective <= 0 || slo.Objective >= 100 {
+			errs.add(indexPath(fieldPath(path, "SLOs"), i)+".Objective", "must be between 0 and 100")
+		}
+		if slo.WindowDays < 0 {
+			errs.add(indexPath(fieldPath(path, "SLOs"), i)+".WindowDays", "can't be negative")
+		}
+	}
+}
+
+// ValidPhone reports whether phone is in E.164 format, like +15551234567
+func ValidPhone(phone string) bool {
+	return phoneRegexp.MatchString(phone)
+}
+
+func checkURL(s, path string, errs *ConfigErrors) {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add(path, "%q isn't an http or https URL", s)
+	}
+}
+
+func checkInterval(seconds int, path string, errs *ConfigErrors) {
+	if seconds < MinInterval || seconds > MaxInterval {
+		errs.add(path, "must be between %d and %d seconds", MinInterval, MaxInterval)
+	}
+}
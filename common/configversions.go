@@ -0,0 +1,127 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// saved configs, newest first
+var redisConfigVersionsKey = "sup:config:versions"
+
+const DefaultConfigVersions = 50
+
+// ConfigVersionType is the config as someone saved it
+type ConfigVersionType struct {
+	Version int64
+	Author  string
+	At      time.Time
+	Note    string `json:",omitempty"` // e.g. which version was rolled back to
+	Config  ConfigType
+}
+
+// newConfigVersions returns the versions to save for a change from the config
+// in before to config. It must be called before MULTI, with the versions watched.
+func newConfigVersions(c redis.Conn, before []byte, config ConfigType, author, note string) [][]byte {
+	versions := [][]byte{}
+	latest, ok := latestConfigVersion(c)
+	if !ok {
+		// keep the config from before there were versions, so the first change can be undone
+		var old ConfigType
+		empty, err := json.Marshal(old)
+		check(err)
+		if string(before) != string(empty) && json.Unmarshal(before, &old) == nil {
+			latest = ConfigVersionType{Version: 1, Author: "unknown", Config: encryptSecrets(old)}
+			data, err := json.Marshal(latest)
+			check(err)
+			versions = append(versions, data)
+		}
+	}
+
+	if author == "" {
+		author = "unknown"
+	}
+	data, err := json.Marshal(ConfigVersionType{
+		Version: latest.Version + 1,
+		Author:  author,
+		At:      time.Now(),
+		Note:    note,
+		Config:  encryptSecrets(config),
+	})
+	check(err)
+	return append(versions, data)
+}
+
+// queueConfigVersions saves the versions inside the caller's MULTI, dropping
+// the oldest beyond what the config says to keep
+func queueConfigVersions(c redis.Conn, versions [][]byte, config ConfigType) {
+	if len(versions) == 0 {
+		return
+	}
+	keep := config.ConfigVersions
+	if keep <= 0 {
+		keep = DefaultConfigVersions
+	}
+	for _, data := range versions {
+		c.Send("LPUSH", redisConfigVersionsKey, data)
+	}
+	c.Send("LTRIM", redisConfigVersionsKey, 0, keep-1)
+}
+
+func latestConfigVersion(c redis.Conn) (ConfigVersionType, bool) {
+	var v ConfigVersionType
+	data, err := redis.Bytes(c.Do("LINDEX", redisConfigVersionsKey, 0))
+	if err == redis.ErrNil {
+		return v, false
+	}
+	check(err)
+	return v, json.Unmarshal(data, &v) == nil
+}
+
+// GetConfigVersions returns the kept versions, newest first
+func GetConfigVersions() []ConfigVersionType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.Strings(c.Do("LRANGE", redisConfigVersionsKey, 0, -1))
+	check(err)
+
+	versions := []ConfigVersionType{}
+	for _, data := range values {
+		var v ConfigVersionType
+		if json.Unmarshal([]byte(data), &v) == nil {
+			v.Config = readConfigSecrets(v.Config)
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+func GetConfigVersion(version int64) (ConfigVersionType, bool) {
+	for _, v := range GetConfigVersions() {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return ConfigVersionType{}, false
+}
+
+// RollbackConfig makes an old version the current config again. This is
+// saved as a new version, so the rollback can itself be undone. It returns
+// what changed, worked out from the config the rollback replaced.
+func RollbackConfig(version int64, author string) (string, error) {
+	old, ok := GetConfigVersion(version)
+	if !ok {
+		return "", fmt.Errorf("no such config version")
+	}
+	var diff string
+	err := updateConfig(author, fmt.Sprintf("rollback to version %d", version), func(c *ConfigType) error {
+		diff = ConfigDiff(*c, old.Config)
+		*c = old.Config
+		return nil
+	})
+	return diff, err
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// lines of unchanged context shown around each change
+const diffContext = 2
+
+// DiffLines compares two texts line by line. Removed lines start with "- ",
+// added lines with "+ " and unchanged context with "  ". Runs of unchanged
+// lines are cut down to "...". It returns "" if the texts are the same.
+func DiffLines(before, after string) string {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	lines := []line{}
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			changed = true
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// keep changed lines and the context around them
+	keep := make([]bool, len(lines))
+	for n, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for k := n - diffContext; k <= n+diffContext; k++ {
+			if k >= 0 && k < len(lines) {
+				keep[k] = true
+			}
+		}
+	}
+
+	var out []string
+	skipped := false
+	for n, l := range lines {
+		if !keep[n] {
+			skipped = true
+			continue
+		}
+		if skipped && len(out) > 0 {
+			out = append(out, "...")
+		}
+		skipped = false
+		out = append(out, string(l.op)+" "+l.text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// ConfigDiff shows what changed between two configs. Secrets are masked,
+// with the ones that changed marked as such.
+func ConfigDiff(before, after ConfigType) string {
+	changed := map[string]bool{}
+	afterSecrets := after.secrets()
+	for name, s := range before.secrets() {
+		changed[name] = *s != *afterSecrets[name]
+	}
+	before, after = before.Masked(), after.Masked()
+	for name, s := range after.secrets() {
+		if changed[name] && *s != "" {
+			*s = SecretMask + " (changed)"
+		}
+	}
+
+	a, err := json.MarshalIndent(before, "", "  ")
+	check(err)
+	b, err := json.MarshalIndent(after, "", "  ")
+	check(err)
+	return DiffLines(string(a), string(b))
+}
@@ -0,0 +1,49 @@
+package common
+
+// Flap detection works like Nagios: the last 21 check states are kept, and the
+// percentage of state changes between them (newer changes weighted more)
+// is the flap score. A target starts flapping when its score goes over
+// FlapStartThreshold and stops once it drops under FlapStopThreshold.
+
+const (
+	flapHistorySize    = 21
+	FlapStartThreshold = 50.0
+	FlapStopThreshold  = 25.0
+)
+
+// RecordState adds the result of a check to the recent states and updates
+// Flapping. It returns true if the target started or stopped flapping.
+func (s *TargetStatusType) RecordState(failing bool) bool {
+	state := "U"
+	if failing {
+		state = "D"
+	}
+	s.RecentStates += state
+	if len(s.RecentStates) > flapHistorySize {
+		s.RecentStates = s.RecentStates[len(s.RecentStates)-flapHistorySize:]
+	}
+
+	score := s.FlapScore()
+	switch {
+	case !s.Flapping && score >= FlapStartThreshold:
+		s.Flapping = true
+		return true
+	case s.Flapping && score < FlapStopThreshold:
+		s.Flapping = false
+		return true
+	}
+	return false
+}
+
+// FlapScore returns the weighted percentage of state changes in the recent
+// states, from 0 (stable) to 100 (changing on every check)
+func (s TargetStatusType) FlapScore() float64 {
+	changes := 0.0
+	for i := 1; i < len(s.RecentStates); i++ {
+		if s.RecentStates[i] != s.RecentStates[i-1] {
+			// oldest change counts 0.8, newest 1.2
+			changes += 0.8 + 0.4*float64(i-1)/float64(flapHistorySize-2)
+		}
+	}
+	return changes * 100 / float64(flapHistorySize-1)
+}
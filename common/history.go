@@ -0,0 +1,146 @@
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisHistoryKeyPrefix = "sup:history:"
+
+// how long check results are kept if the config doesn't say
+const DefaultHistoryDays = 30
+
+// CheckResultType is the result of checking one target from one location
+type CheckResultType struct {
+	At          time.Time
+	Target      string
+	Location    string
+	StatusCode  int
+	LatencyMs   int64
+	Error       string `json:",omitempty"`
+	Up          bool
+	State       string     `json:",omitempty"` // the target's overall state after this check. only set by the central worker
+	Maintenance bool       `json:",omitempty"` // whether a maintenance window covered the target
+	CertExpiry  *time.Time `json:",omitempty"` // when the TLS certificate expires, for https targets
+}
+
+// HistoryPageType is one page of check results, newest first. Pass Next as
+// `before` and Skip as `skip` to get the following page; Next is 0 on the
+// last page. Results from several locations can share a millisecond, so Skip
+// says how many of the results at Next were already returned.
+type HistoryPageType struct {
+	Results []CheckResultType
+	Next    int64
+	Skip    int
+}
+
+func (c ConfigType) HistoryRetention() time.Duration {
+	days := c.HistoryDays
+	if days <= 0 {
+		days = DefaultHistoryDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// HistoryRetentionFor is how long the target's results are kept. Targets on
+// the status page keep enough for all of its uptime bars.
+func (c ConfigType) HistoryRetentionFor(target string) time.Duration {
+	retention := c.HistoryRetention()
+	for _, component := range c.StatusPage.Components {
+		if component.HasTarget(target) {
+			return max(retention, StatusPageDays*24*time.Hour)
+		}
+	}
+	return retention
+}
+
+func unixMs(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// AddCheckResult appends the result to its target's history and latency
+// histogram and trims anything older than the retention period. History is
+// only trimmed by age, since every location's results share it and any cap
+// on their number would cut the retention short.
+func AddCheckResult(result CheckResultType, retention time.Duration) {
+	data, err := json.Marshal(result)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	key := redisHistoryKeyPrefix + result.Target
+	c.Send("MULTI")
+	c.Send("ZADD", key, unixMs(result.At), data)
+	c.Send("ZREMRANGEBYSCORE", key, "-inf", "("+strconv.FormatInt(unixMs(result.At.Add(-retention)), 10))
+	sendLatency(c, result)
+	_, err = c.Do("EXEC")
+	check(err)
+}
+
+// GetHistory returns up to limit results for the target at or before the
+// given unix millisecond timestamp (0 for the latest), newest first, leaving
+// out the first skip results at that timestamp
+func GetHistory(target string, before int64, skip, limit int) HistoryPageType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	max := "+inf"
+	if before > 0 {
+		max = strconv.FormatInt(before, 10)
+	} else {
+		skip = 0
+	}
+
+	values, err := redis.Values(c.Do("ZREVRANGEBYSCORE", redisHistoryKeyPrefix+target, max, "-inf", "WITHSCORES", "LIMIT", skip, limit))
+	check(err)
+
+	page := HistoryPageType{Results: []CheckResultType{}}
+	count := len(values) / 2
+	for len(values) >= 2 {
+		var data []byte
+		var score int64
+		values, err = redis.Scan(values, &data, &score)
+		check(err)
+
+		var r CheckResultType
+		if json.Unmarshal(data, &r) == nil {
+			page.Results = append(page.Results, r)
+		}
+		if score != page.Next {
+			page.Next, page.Skip = score, 0
+		}
+		page.Skip++
+	}
+	if page.Next == before {
+		page.Skip += skip
+	}
+	if count < limit {
+		page.Next, page.Skip = 0, 0
+	}
+	return page
+}
+
+// GetHistoryRange returns all results for the target between from and to, oldest first
+func GetHistoryRange(target string, from, to time.Time) []CheckResultType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.Strings(c.Do("ZRANGEBYSCORE", redisHistoryKeyPrefix+target, unixMs(from), unixMs(to)))
+	check(err)
+
+	results := []CheckResultType{}
+	for _, data := range values {
+		var r CheckResultType
+		if json.Unmarshal([]byte(data), &r) == nil {
+			results = append(results, r)
+		}
+	}
+	return results
+}
@@ -0,0 +1,209 @@
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisIncidentKeyPrefix = "sup:incident:"
+var redisIncidentsKey = "sup:incidents"
+
+// kinds of incident timeline events
+const (
+	EventOpened       = "opened"
+	EventAffected     = "affected"
+	EventNotified     = "notified"
+	EventCalled       = "called"
+	EventAcknowledged = "acknowledged"
+	EventNote         = "note"
+	EventResolved     = "resolved"
+)
+
+// IncidentType is the record of one outage of a target
+type IncidentType struct {
+	ID         string
+	Target     string
+	StartedAt  time.Time
+	EndedAt    time.Time // zero while the incident is open
+	FirstError string
+	AckedBy    string
+	AckedAt    time.Time
+	Affected   []string // targets that were unreachable because of this outage
+	Timeline   []IncidentEventType
+}
+
+type IncidentEventType struct {
+	At   time.Time
+	Kind string
+	By   string `json:",omitempty"`
+	Text string
+}
+
+// IncidentPageType is one page of incidents, newest first. Pass Next as
+// `before` to get the following page; it is 0 on the last page.
+type IncidentPageType struct {
+	Incidents []IncidentType
+	Next      int64
+}
+
+func (i IncidentType) IsOpen() bool {
+	return i.EndedAt.IsZero()
+}
+
+// Duration is how long the incident lasted, or has lasted so far, to the second
+func (i IncidentType) Duration() time.Duration {
+	end := i.EndedAt
+	if i.IsOpen() {
+		end = time.Now()
+	}
+	return end.Sub(i.StartedAt).Round(time.Second)
+}
+
+func NewIncidentID() string {
+	return newID()
+}
+
+func CreateIncident(incident IncidentType) {
+	incident.Timeline = append(incident.Timeline, IncidentEventType{
+		At:   incident.StartedAt,
+		Kind: EventOpened,
+		Text: incident.FirstError,
+	})
+
+	data, err := json.Marshal(incident)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("SET", redisIncidentKeyPrefix+incident.ID, data)
+	c.Send("ZADD", redisIncidentsKey, unixMs(incident.StartedAt), incident.ID)
+	_, err = c.Do("EXEC")
+	check(err)
+}
+
+// GetIncident loads an incident. The bool is false if there is no such incident.
+func GetIncident(id string) (IncidentType, bool) {
+	var incident IncidentType
+	if id == "" {
+		return incident, false
+	}
+	ok := getJSON(redisIncidentKeyPrefix+id, &incident)
+	return incident, ok
+}
+
+// UpdateIncident applies fn to the stored incident, retrying if someone else
+// changed it at the same time. It returns false if there is no such incident.
+func UpdateIncident(id string, fn func(*IncidentType)) bool {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	key := redisIncidentKeyPrefix + id
+	for {
+		_, err = c.Do("WATCH", key)
+		check(err)
+
+		data, err := redis.Bytes(c.Do("GET", key))
+		if err == redis.ErrNil {
+			c.Do("UNWATCH")
+			return false
+		}
+		check(err)
+
+		var incident IncidentType
+		check(json.Unmarshal(data, &incident))
+		fn(&incident)
+		data, err = json.Marshal(incident)
+		check(err)
+
+		c.Send("MULTI")
+		c.Send("SET", key, data)
+		reply, err := c.Do("EXEC")
+		check(err)
+		if reply != nil {
+			return true
+		}
+	}
+}
+
+// AddIncidentEvent appends an event to the incident's timeline
+func AddIncidentEvent(id string, event IncidentEventType) bool {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	return UpdateIncident(id, func(i *IncidentType) {
+		i.Timeline = append(i.Timeline, event)
+	})
+}
+
+func AcknowledgeIncident(id, by string) bool {
+	now := time.Now()
+	return UpdateIncident(id, func(i *IncidentType) {
+		if i.AckedBy != "" {
+			return
+		}
+		i.AckedBy = by
+		i.AckedAt = now
+		i.Timeline = append(i.Timeline, IncidentEventType{At: now, Kind: EventAcknowledged, By: by})
+	})
+}
+
+// AddAffectedTarget folds a target that is unreachable because of this
+// incident's target into the incident
+func AddAffectedTarget(id, target string) bool {
+	return UpdateIncident(id, func(i *IncidentType) {
+		for _, t := range i.Affected {
+			if t == target {
+				return
+			}
+		}
+		i.Affected = append(i.Affected, target)
+		i.Timeline = append(i.Timeline, IncidentEventType{At: time.Now(), Kind: EventAffected, Text: target + " is unreachable"})
+	})
+}
+
+func ResolveIncident(id string, at time.Time) bool {
+	return UpdateIncident(id, func(i *IncidentType) {
+		i.EndedAt = at
+		i.Timeline = append(i.Timeline, IncidentEventType{At: at, Kind: EventResolved})
+	})
+}
+
+// GetIncidents returns up to limit incidents that started before the given
+// unix millisecond timestamp (0 for the latest), newest first
+func GetIncidents(before int64, limit int) IncidentPageType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	max := "+inf"
+	if before > 0 {
+		max = "(" + strconv.FormatInt(before, 10)
+	}
+
+	values, err := redis.Values(c.Do("ZREVRANGEBYSCORE", redisIncidentsKey, max, "-inf", "WITHSCORES", "LIMIT", 0, limit))
+	check(err)
+
+	page := IncidentPageType{Incidents: []IncidentType{}}
+	for len(values) >= 2 {
+		var id string
+		var score int64
+		values, err = redis.Scan(values, &id, &score)
+		check(err)
+
+		if incident, ok := GetIncident(id); ok {
+			page.Incidents = append(page.Incidents, incident)
+		}
+		page.Next = score
+	}
+	if len(page.Incidents) < limit {
+		page.Next = 0
+	}
+	return page
+}
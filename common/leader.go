@@ -0,0 +1,102 @@
+package common
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisLeaderKey = "sup:leader"
+var redisFenceKey = "sup:leader:fence"
+
+// fenceToken is the token this process got when it became leader. While it is
+// set, state writes only go through if the token is still the latest one, so a
+// worker that lost its lease without noticing can't clobber the new leader.
+var fenceToken int64
+
+// takes the lease if it is free or renews it if we already hold it. returns the
+// fence token, or 0 if someone else holds the lease
+var acquireLeaseScript = redis.NewScript(2, `
+local holder = redis.call("GET", KEYS[1])
+if not holder then
+	local token = redis.call("INCR", KEYS[2])
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return token
+elseif holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return tonumber(redis.call("GET", KEYS[2]))
+end
+return 0
+`)
+
+var releaseLeaseScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// sets KEYS[2] to ARGV[2] only if the fence in KEYS[1] still equals ARGV[1]
+var fencedSetScript = redis.NewScript(2, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[2], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// AcquireLease takes or renews the worker leader lease for id. It returns the
+// fence token and whether id is now the leader.
+func AcquireLease(id string, ttl time.Duration) (int64, bool) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	token, err := redis.Int64(acquireLeaseScript.Do(c, redisLeaderKey, redisFenceKey, id, int64(ttl/time.Millisecond)))
+	check(err)
+	return token, token > 0
+}
+
+func ReleaseLease(id string) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = releaseLeaseScript.Do(c, redisLeaderKey, id)
+	check(err)
+}
+
+func GetLeader() string {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	leader, err := redis.String(c.Do("GET", redisLeaderKey))
+	if err == redis.ErrNil {
+		return ""
+	}
+	check(err)
+	return leader
+}
+
+// SetFenceToken makes later state writes from this process conditional on
+// token still being current. 0 turns fencing off.
+func SetFenceToken(token int64) {
+	atomic.StoreInt64(&fenceToken, token)
+}
+
+// fencedSet writes data to key, unless this process is fenced and a newer
+// leader has taken over. It reports whether the write happened.
+func fencedSet(c redis.Conn, key string, data []byte) bool {
+	token := atomic.LoadInt64(&fenceToken)
+	if token == 0 {
+		_, err := c.Do("SET", key, data)
+		check(err)
+		return true
+	}
+
+	ok, err := redis.Bool(fencedSetScript.Do(c, redisFenceKey, key, token, data))
+	check(err)
+	return ok
+}
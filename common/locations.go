@@ -0,0 +1,63 @@
+package common
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisLocationsKeyPrefix = "sup:locations:"
+
+// location name used by checks made by the central worker itself
+const DefaultLocation = "local"
+
+// SetLocationResult saves the result as the latest check of its target from its location
+func SetLocationResult(result CheckResultType) {
+	data, err := json.Marshal(result)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("HSET", redisLocationsKeyPrefix+result.Target, result.Location, data)
+	check(err)
+}
+
+// GetLocationResults returns the latest result from every location that has
+// checked the target, keyed by location
+func GetLocationResults(target string) map[string]CheckResultType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisLocationsKeyPrefix+target))
+	check(err)
+
+	results := map[string]CheckResultType{}
+	for loc, data := range values {
+		var r CheckResultType
+		if json.Unmarshal([]byte(data), &r) == nil {
+			results[loc] = r
+		}
+	}
+	return results
+}
+
+// LocationNames returns the sorted names of all locations that reported on
+// any of the targets
+func LocationNames(results []map[string]CheckResultType) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, m := range results {
+		for loc := range m {
+			if !seen[loc] {
+				seen[loc] = true
+				names = append(names, loc)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
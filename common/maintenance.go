@@ -0,0 +1,191 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisMaintenanceKey = "sup:maintenance"
+
+// MaintenanceWindowType silences calls for some targets while it is active.
+// Checks keep running and recording results. A window is either one-off
+// (Start to End) or recurring (every time Schedule fires, for Duration minutes).
+type MaintenanceWindowType struct {
+	ID      string
+	Reason  string
+	Targets []string // target names. a window with no targets or tags covers everything
+	Tags    []string
+
+	Start time.Time
+	End   time.Time
+
+	Schedule string // cron expression for recurring windows
+	Duration int    // minutes a recurring window lasts
+	Timezone string
+}
+
+func (m MaintenanceWindowType) IsRecurring() bool {
+	return m.Schedule != ""
+}
+
+// IsActive reports whether the window covers the given time
+func (m MaintenanceWindowType) IsActive(now time.Time) bool {
+	if !m.IsRecurring() {
+		return !now.Before(m.Start) && now.Before(m.End)
+	}
+
+	cron, err := ParseCron(m.Schedule)
+	if err != nil {
+		return false
+	}
+	loc := loadLocation(m.Timezone)
+	duration := time.Duration(m.Duration) * time.Minute
+	// the window is open if the schedule fired within the last Duration
+	for start := cron.Next(now.In(loc).Add(-duration - time.Minute)); !start.IsZero() && !start.After(now); start = cron.Next(start) {
+		if now.Before(start.Add(duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the window is either a one-off window with an end
+// after its start or a recurring window with a schedule and duration
+func (m MaintenanceWindowType) Validate() error {
+	if m.Timezone != "" {
+		if _, err := time.LoadLocation(m.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", m.Timezone)
+		}
+	}
+	if !m.IsRecurring() {
+		if m.Start.IsZero() || !m.End.After(m.Start) {
+			return fmt.Errorf("one-off windows need a Start and a later End")
+		}
+		return nil
+	}
+	if _, err := ParseCron(m.Schedule); err != nil {
+		return err
+	}
+	if m.Duration <= 0 {
+		return fmt.Errorf("recurring windows need a positive Duration in minutes")
+	}
+	return nil
+}
+
+// IsExpired reports whether a one-off window is over. Recurring windows never expire.
+func (m MaintenanceWindowType) IsExpired(now time.Time) bool {
+	return !m.IsRecurring() && !now.Before(m.End)
+}
+
+// Covers reports whether the window applies to the target
+func (m MaintenanceWindowType) Covers(target TargetType) bool {
+	if len(m.Targets) == 0 && len(m.Tags) == 0 {
+		return true
+	}
+	for _, name := range m.Targets {
+		if name == target.Name {
+			return true
+		}
+	}
+	for _, tag := range m.Tags {
+		if target.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMaintenanceWindows returns all windows that haven't expired, soonest
+// first. Expired windows are deleted as they are found.
+func GetMaintenanceWindows() []MaintenanceWindowType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisMaintenanceKey))
+	check(err)
+
+	now := time.Now()
+	windows := []MaintenanceWindowType{}
+	for id, data := range values {
+		var m MaintenanceWindowType
+		if json.Unmarshal([]byte(data), &m) != nil {
+			continue
+		}
+		if m.IsExpired(now) {
+			_, err = c.Do("HDEL", redisMaintenanceKey, id)
+			check(err)
+			continue
+		}
+		windows = append(windows, m)
+	}
+
+	sort.Sort(byStart(windows))
+	return windows
+}
+
+// AddMaintenanceWindow saves the window, giving it an ID if it doesn't have one
+func AddMaintenanceWindow(m MaintenanceWindowType) MaintenanceWindowType {
+	if m.ID == "" {
+		m.ID = newID()
+	}
+
+	data, err := json.Marshal(m)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("HSET", redisMaintenanceKey, m.ID, data)
+	check(err)
+	return m
+}
+
+// GetMaintenanceWindow loads one window. The bool is false if there is no such window.
+func GetMaintenanceWindow(id string) (MaintenanceWindowType, bool) {
+	for _, m := range GetMaintenanceWindows() {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return MaintenanceWindowType{}, false
+}
+
+func DeleteMaintenanceWindow(id string) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("HDEL", redisMaintenanceKey, id)
+	check(err)
+}
+
+// InMaintenance reports whether an active window covers the target
+func InMaintenance(target TargetType, now time.Time) bool {
+	for _, m := range GetMaintenanceWindows() {
+		if m.IsActive(now) && m.Covers(target) {
+			return true
+		}
+	}
+	return false
+}
+
+type byStart []MaintenanceWindowType
+
+func (s byStart) Len() int           { return len(s) }
+func (s byStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byStart) Less(i, j int) bool { return s[i].Start.Before(s[j].Start) }
+
+func newID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	check(err)
+	return hex.EncodeToString(b)
+}
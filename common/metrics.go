@@ -0,0 +1,139 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisLatencyKeyPrefix = "sup:metrics:latency:"
+var redisNotificationsKey = "sup:metrics:notifications"
+
+// notification channels, for the notification counters
+const (
+	ChannelHipchat = "hipchat"
+	ChannelPhone   = "phone"
+)
+
+// Channels lists every notification channel, so counters show up before the
+// first notification is sent
+var Channels = []string{ChannelHipchat, ChannelPhone}
+
+// LatencyBuckets are the upper bounds, in seconds, of the check latency histogram
+var LatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20}
+
+// LatencyHistogramType is the latency histogram of one target from one
+// location. Buckets are cumulative, one per LatencyBuckets entry.
+type LatencyHistogramType struct {
+	Buckets    []int64
+	Count      int64
+	SumSeconds float64
+}
+
+type NotificationCountType struct {
+	Sent   int64
+	Failed int64
+}
+
+// sendLatency queues the commands that add the result to its latency
+// histogram. Fields are "location|le", "location|count" and "location|sum_ms".
+func sendLatency(c redis.Conn, result CheckResultType) {
+	key := redisLatencyKeyPrefix + result.Target
+	for _, le := range LatencyBuckets {
+		if float64(result.LatencyMs)/1000 <= le {
+			c.Send("HINCRBY", key, result.Location+"|"+formatBucket(le), 1)
+		}
+	}
+	c.Send("HINCRBY", key, result.Location+"|count", 1)
+	c.Send("HINCRBY", key, result.Location+"|sum_ms", result.LatencyMs)
+}
+
+// GetLatencyHistograms returns the target's latency histograms, keyed by location
+func GetLatencyHistograms(target string) map[string]LatencyHistogramType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisLatencyKeyPrefix+target))
+	check(err)
+
+	histograms := map[string]LatencyHistogramType{}
+	for field, value := range values {
+		i := strings.LastIndex(field, "|")
+		n, err := strconv.ParseInt(value, 10, 64)
+		if i < 0 || err != nil {
+			continue
+		}
+		loc, name := field[:i], field[i+1:]
+
+		h, ok := histograms[loc]
+		if !ok {
+			h.Buckets = make([]int64, len(LatencyBuckets))
+		}
+		switch name {
+		case "count":
+			h.Count = n
+		case "sum_ms":
+			h.SumSeconds = float64(n) / 1000
+		default:
+			for b, le := range LatencyBuckets {
+				if formatBucket(le) == name {
+					h.Buckets[b] = n
+				}
+			}
+		}
+		histograms[loc] = h
+	}
+	return histograms
+}
+
+func formatBucket(le float64) string {
+	return strconv.FormatFloat(le, 'g', -1, 64)
+}
+
+// CountNotification counts a notification sent on the channel, as failed if
+// err is not nil
+func CountNotification(channel string, err error) {
+	field := channel + "|sent"
+	if err != nil {
+		field = channel + "|failed"
+	}
+
+	c, cerr := getRedis()
+	check(cerr)
+	defer c.Close()
+
+	_, cerr = c.Do("HINCRBY", redisNotificationsKey, field, 1)
+	check(cerr)
+}
+
+// GetNotificationCounts returns the notification counters of every channel
+func GetNotificationCounts() map[string]NotificationCountType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisNotificationsKey))
+	check(err)
+
+	counts := map[string]NotificationCountType{}
+	for _, channel := range Channels {
+		counts[channel] = NotificationCountType{}
+	}
+	for field, value := range values {
+		i := strings.LastIndex(field, "|")
+		n, err := strconv.ParseInt(value, 10, 64)
+		if i < 0 || err != nil {
+			continue
+		}
+		count := counts[field[:i]]
+		if field[i+1:] == "failed" {
+			count.Failed = n
+		} else {
+			count.Sent = n
+		}
+		counts[field[:i]] = count
+	}
+	return counts
+}
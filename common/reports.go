@@ -0,0 +1,89 @@
+package common
+
+import (
+	"time"
+)
+
+// UptimeReportType summarizes a target's availability over a time range.
+// Times are in seconds.
+type UptimeReportType struct {
+	Target          string
+	From            time.Time
+	To              time.Time
+	Checks          int
+	UptimePercent   float64
+	UptimeSeconds   float64
+	DowntimeSeconds float64
+	Outages         int
+	MTTR            float64 // mean time to recovery: average outage length
+	MTBF            float64 // mean time between failures: uptime per outage
+}
+
+// UptimeReport works out availability from the central worker's check
+// results, which must be sorted oldest first. Each result's state is taken to
+// hold until the next result. If excludeMaintenance is set, time covered by
+// maintenance windows counts as neither up nor down.
+func UptimeReport(target string, results []CheckResultType, from, to time.Time, excludeMaintenance bool) UptimeReportType {
+	report := UptimeReportType{Target: target, From: from, To: to}
+
+	var prev *CheckResultType
+	outageStart := time.Time{}
+	var outageTotal time.Duration
+	var up, down time.Duration
+
+	// adds the time from r until the next result to the up or down total
+	account := func(r *CheckResultType, until time.Time) {
+		if excludeMaintenance && r.Maintenance {
+			return
+		}
+		if r.State == StateUp {
+			up += until.Sub(r.At)
+		} else {
+			down += until.Sub(r.At)
+		}
+	}
+
+	for i := range results {
+		r := &results[i]
+		if r.State == "" || r.At.Before(from) || r.At.After(to) {
+			continue
+		}
+		report.Checks++
+		failing := r.State != StateUp
+
+		if prev != nil {
+			account(prev, r.At)
+		}
+
+		if failing && outageStart.IsZero() && !(excludeMaintenance && r.Maintenance) {
+			outageStart = r.At
+			report.Outages++
+		} else if !failing && !outageStart.IsZero() {
+			outageTotal += r.At.Sub(outageStart)
+			outageStart = time.Time{}
+		}
+		prev = r
+	}
+
+	if prev != nil {
+		end := to
+		if now := time.Now(); end.After(now) {
+			end = now
+		}
+		account(prev, end)
+		if !outageStart.IsZero() {
+			outageTotal += end.Sub(outageStart)
+		}
+	}
+
+	report.UptimeSeconds = up.Seconds()
+	report.DowntimeSeconds = down.Seconds()
+	if up+down > 0 {
+		report.UptimePercent = 100 * up.Seconds() / (up + down).Seconds()
+	}
+	if report.Outages > 0 {
+		report.MTTR = outageTotal.Seconds() / float64(report.Outages)
+		report.MTBF = up.Seconds() / float64(report.Outages)
+	}
+	return report
+}
@@ -0,0 +1,255 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// SecretMask stands in for secrets wherever the config is shown. Saving it
+// back keeps the secret as it was, so secrets are write-only.
+const SecretMask = "********"
+
+// encrypted secrets are stored as enc:v1:<key id>:<base64 nonce and ciphertext>
+const secretPrefix = "enc:v1:"
+
+type secretKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// secretKeys decrypt secrets in redis. The first one also encrypts them.
+var secretKeys []secretKey
+
+var undecryptableOnce sync.Once
+
+// SetSecretKeys sets the keys secrets are encrypted with in redis. Each key is
+// 32 bytes, written as base64 or hex. The first key encrypts; the rest are
+// old keys that are still tried when decrypting, for key rotation. With no
+// keys, secrets are stored as they are.
+func SetSecretKeys(keys []string) error {
+	parsed := []secretKey{}
+	for _, s := range keys {
+		key, err := parseSecretKey(s)
+		if err != nil {
+			return err
+		}
+		block, err := aes.NewCipher(key)
+		check(err)
+		aead, err := cipher.NewGCM(block)
+		check(err)
+		sum := sha256.Sum256(key)
+		parsed = append(parsed, secretKey{id: hex.EncodeToString(sum[:4]), aead: aead})
+	}
+	secretKeys = parsed
+	return nil
+}
+
+func parseSecretKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("secret keys must be 32 bytes, written as base64 or hex")
+}
+
+// NewSecretKey makes a random key for SetSecretKeys
+func NewSecretKey() string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	check(err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// SecretsEncrypted is whether secrets are encrypted when the config is saved
+func SecretsEncrypted() bool {
+	return len(secretKeys) > 0
+}
+
+// secrets are the config fields that hold passwords and tokens
+func (config *ConfigType) secrets() map[string]*string {
+	return map[string]*string{
+		"TwilioAuthToken":  &config.TwilioAuthToken,
+		"HipchatAuthToken": &config.HipchatAuthToken,
+	}
+}
+
+// Masked returns the config with secrets that are set replaced by SecretMask,
+// for showing to people
+func (config ConfigType) Masked() ConfigType {
+	for _, s := range config.secrets() {
+		if *s != "" {
+			*s = SecretMask
+		}
+	}
+	return config
+}
+
+// KeepSecrets puts back the secrets from current that were left as SecretMask
+func (config *ConfigType) KeepSecrets(current ConfigType) {
+	currentSecrets := current.secrets()
+	for name, s := range config.secrets() {
+		if *s == SecretMask {
+			*s = *currentSecrets[name]
+		}
+	}
+}
+
+func encryptSecret(s string) string {
+	if s == "" || len(secretKeys) == 0 || strings.HasPrefix(s, secretPrefix) {
+		return s
+	}
+	key := secretKeys[0]
+	nonce := make([]byte, key.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	check(err)
+	sealed := key.aead.Seal(nonce, nonce, []byte(s), nil)
+	return secretPrefix + key.id + ":" + base64.StdEncoding.EncodeToString(sealed)
+}
+
+func decryptSecret(s string) (string, error) {
+	if !strings.HasPrefix(s, secretPrefix) {
+		return s, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(s, secretPrefix), ":", 2)
+	if len(parts) != 2 {
+		return s, fmt.Errorf("malformed encrypted secret")
+	}
+	for _, key := range secretKeys {
+		if key.id != parts[0] {
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil || len(sealed) < key.aead.NonceSize() {
+			return s, fmt.Errorf("malformed encrypted secret")
+		}
+		n := key.aead.NonceSize()
+		plain, err := key.aead.Open(nil, sealed[:n], sealed[n:], nil)
+		if err != nil {
+			return s, fmt.Errorf("secret doesn't decrypt with key %s", key.id)
+		}
+		return string(plain), nil
+	}
+	return s, fmt.Errorf("secret was encrypted with key %s, which isn't set", parts[0])
+}
+
+// encryptSecrets returns the config as it is stored in redis
+func encryptSecrets(config ConfigType) ConfigType {
+	for _, s := range config.secrets() {
+		*s = encryptSecret(*s)
+	}
+	return config
+}
+
+// decryptSecrets decrypts the config's secrets in place. Secrets that can't
+// be decrypted are left encrypted, so saving the config doesn't lose them.
+func decryptSecrets(config *ConfigType) error {
+	var failed []string
+	for name, s := range config.secrets() {
+		plain, err := decryptSecret(*s)
+		if err != nil {
+			failed = append(failed, name+": "+err.Error())
+			continue
+		}
+		*s = plain
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("can't decrypt %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// readConfig parses a config as stored in redis
+func readConfig(data []byte) ConfigType {
+	var config ConfigType
+	json.Unmarshal(data, &config)
+	return readConfigSecrets(config)
+}
+
+// readConfigSecrets decrypts a config read from redis, logging once if
+// something can't be decrypted
+func readConfigSecrets(config ConfigType) ConfigType {
+	if err := decryptSecrets(&config); err != nil {
+		undecryptableOnce.Do(func() {
+			log.Printf("%s. check --secret_key", err)
+		})
+	}
+	return config
+}
+
+// RotateSecrets encrypts the secrets in the config and every kept version
+// with the first key, so the other keys can be retired. It fails if a secret
+// can't be decrypted with any of the keys.
+func RotateSecrets() error {
+	if len(secretKeys) == 0 {
+		return fmt.Errorf("no secret key is set")
+	}
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	for {
+		_, err = c.Do("WATCH", redisConfigKey, redisConfigVersionsKey)
+		check(err)
+
+		var stored []ConfigType
+		data, err := redis.Bytes(c.Do("GET", redisConfigKey))
+		if err != redis.ErrNil {
+			check(err)
+		}
+		var config ConfigType
+		json.Unmarshal(data, &config)
+		stored = append(stored, config)
+
+		versions := []ConfigVersionType{}
+		values, err := redis.Strings(c.Do("LRANGE", redisConfigVersionsKey, 0, -1))
+		check(err)
+		for _, data := range values {
+			var v ConfigVersionType
+			if json.Unmarshal([]byte(data), &v) == nil {
+				versions = append(versions, v)
+				stored = append(stored, v.Config)
+			}
+		}
+
+		for i := range stored {
+			if err := decryptSecrets(&stored[i]); err != nil {
+				c.Do("UNWATCH")
+				return err
+			}
+		}
+
+		c.Send("MULTI")
+		if data != nil {
+			data, err = json.Marshal(encryptSecrets(stored[0]))
+			check(err)
+			c.Send("SET", redisConfigKey, data)
+		}
+		c.Send("DEL", redisConfigVersionsKey)
+		for i, v := range versions {
+			v.Config = encryptSecrets(stored[i+1])
+			data, err := json.Marshal(v)
+			check(err)
+			c.Send("RPUSH", redisConfigVersionsKey, data)
+		}
+		reply, err := c.Do("EXEC")
+		check(err)
+		if reply != nil {
+			return nil
+		}
+	}
+}
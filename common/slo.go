@@ -0,0 +1,149 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+const DefaultSLOWindowDays = 30
+
+// SLOType is a service level objective for a target, e.g. 99.9% of checks
+// succeed, or 95% of checks succeed in under 500ms
+type SLOType struct {
+	Name       string
+	Objective  float64 // percent of checks that must be good
+	LatencyMs  int64   // if set, a check is only good if it is also faster than this
+	WindowDays int     // period the objective is measured over. defaults to 30
+}
+
+// BurnAlertType is a multi-window burn-rate alert. It fires when the error
+// budget is being used up more than Threshold times faster than the SLO
+// allows, over both the long and the short window. The short window makes the
+// alert stop soon after the problem does.
+type BurnAlertType struct {
+	Name      string
+	Long      time.Duration
+	Short     time.Duration
+	Threshold float64
+}
+
+var BurnAlerts = []BurnAlertType{
+	{Name: "fast", Long: time.Hour, Short: 5 * time.Minute, Threshold: 14.4},
+	{Name: "slow", Long: 6 * time.Hour, Short: 30 * time.Minute, Threshold: 6},
+}
+
+// SLOReportType is how an SLO is doing over its window
+type SLOReportType struct {
+	Target          string
+	SLO             SLOType
+	Checks          int
+	GoodPercent     float64
+	BudgetRemaining float64 // fraction of the error budget left. negative once it's blown
+	BurnRates       map[string]float64
+}
+
+func (s SLOType) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	if s.LatencyMs > 0 {
+		return fmt.Sprintf("%g%% under %dms", s.Objective, s.LatencyMs)
+	}
+	return fmt.Sprintf("%g%% up", s.Objective)
+}
+
+func (s SLOType) Window() time.Duration {
+	days := s.WindowDays
+	if days <= 0 {
+		days = DefaultSLOWindowDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// errorBudget is the fraction of checks allowed to be bad
+func (s SLOType) errorBudget() float64 {
+	return 1 - s.Objective/100
+}
+
+// counts reports whether the result is one of the central worker's checks that
+// the SLO should count. Checks during maintenance don't count.
+func (s SLOType) counts(r CheckResultType) bool {
+	return r.State != "" && !r.Maintenance
+}
+
+func (s SLOType) IsGood(r CheckResultType) bool {
+	if r.State != StateUp {
+		return false
+	}
+	return s.LatencyMs <= 0 || r.LatencyMs < s.LatencyMs
+}
+
+// tally returns how many counted results at or after since are bad, and how
+// many were counted
+func (s SLOType) tally(results []CheckResultType, since time.Time) (int, int) {
+	bad, total := 0, 0
+	for _, r := range results {
+		if r.At.Before(since) || !s.counts(r) {
+			continue
+		}
+		total++
+		if !s.IsGood(r) {
+			bad++
+		}
+	}
+	return bad, total
+}
+
+// badFraction returns the fraction of counted results at or after since that
+// are bad, and how many were counted
+func (s SLOType) badFraction(results []CheckResultType, since time.Time) (float64, int) {
+	bad, total := s.tally(results, since)
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(bad) / float64(total), total
+}
+
+// BurnRate is how many times faster than allowed the error budget was used
+// since the given time. 1 means the budget would run out exactly at the end of
+// the window.
+func (s SLOType) BurnRate(results []CheckResultType, since time.Time) float64 {
+	budget := s.errorBudget()
+	if budget <= 0 {
+		return 0
+	}
+	bad, _ := s.badFraction(results, since)
+	return bad / budget
+}
+
+// IsBurning reports whether the alert's long and short windows both burn
+// faster than its threshold, with at least minBad bad checks in the short
+// window. With a tight objective a single bad check burns far faster than
+// any threshold, so minBad keeps one blip from firing the alert.
+func (s SLOType) IsBurning(alert BurnAlertType, results []CheckResultType, now time.Time, minBad int) bool {
+	if bad, _ := s.tally(results, now.Add(-alert.Short)); bad < minBad {
+		return false
+	}
+	return s.BurnRate(results, now.Add(-alert.Long)) >= alert.Threshold &&
+		s.BurnRate(results, now.Add(-alert.Short)) >= alert.Threshold
+}
+
+// Report works out how the SLO is doing from results covering its whole window
+func (s SLOType) Report(target string, results []CheckResultType, now time.Time) SLOReportType {
+	bad, total := s.badFraction(results, now.Add(-s.Window()))
+	report := SLOReportType{
+		Target:          target,
+		SLO:             s,
+		Checks:          total,
+		GoodPercent:     100 * (1 - bad),
+		BudgetRemaining: 1,
+		BurnRates:       map[string]float64{},
+	}
+	if budget := s.errorBudget(); budget > 0 {
+		report.BudgetRemaining = 1 - bad/budget
+	}
+	for _, alert := range BurnAlerts {
+		report.BurnRates[alert.Name] = s.BurnRate(results, now.Add(-alert.Long))
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+// minuteChecks is an hour of checks a minute apart ending at now, with the
+// last failed of them down
+func minuteChecks(now time.Time, failed int) []CheckResultType {
+	results := []CheckResultType{}
+	for i := 59; i >= 0; i-- {
+		r := CheckResultType{At: now.Add(-time.Duration(i) * time.Minute), State: StateUp, Up: true}
+		if i < failed {
+			r.State, r.Up = StateDown, false
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestIsBurningNeedsSeveralBadChecks(t *testing.T) {
+	now := time.Now()
+	slo := SLOType{Objective: 99.9}
+	fast := BurnAlerts[0]
+
+	// one failed check out of an hour burns the budget far faster than the
+	// threshold, but is a blip
+	results := minuteChecks(now, 1)
+	if rate := slo.BurnRate(results, now.Add(-fast.Short)); rate < fast.Threshold {
+		t.Fatalf("one failed check burns at %g, expected more than %g", rate, fast.Threshold)
+	}
+	if slo.IsBurning(fast, results, now, 5) {
+		t.Errorf("one failed check fired the %s alert", fast.Name)
+	}
+
+	if slo.IsBurning(fast, minuteChecks(now, 4), now, 5) {
+		t.Errorf("four failed checks fired the %s alert", fast.Name)
+	}
+	if !slo.IsBurning(fast, minuteChecks(now, 5), now, 5) {
+		t.Errorf("five failed checks didn't fire the %s alert", fast.Name)
+	}
+}
+
+func TestIsBurningIgnoresMaintenance(t *testing.T) {
+	now := time.Now()
+	slo := SLOType{Objective: 99.9}
+	results := minuteChecks(now, 10)
+	for i := range results {
+		results[i].Maintenance = true
+	}
+	for _, alert := range BurnAlerts {
+		if slo.IsBurning(alert, results, now, 1) {
+			t.Errorf("checks during maintenance fired the %s alert", alert.Name)
+		}
+	}
+}
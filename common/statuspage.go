@@ -0,0 +1,52 @@
+package common
+
+// DefaultStatusPageTitle is shown when the status page has no title
+const DefaultStatusPageTitle = "Status"
+
+// StatusPageDays is how many days of uptime bars the public page shows.
+// Targets on the page keep at least this much history.
+const StatusPageDays = 90
+
+// StatusPageType configures the public status page. Only targets that belong
+// to a component are shown, and only by component name.
+type StatusPageType struct {
+	Title      string
+	Components []ComponentType
+}
+
+// ComponentType groups targets that the public sees as one service
+type ComponentType struct {
+	Name        string
+	Description string
+	Targets     []string // target names
+}
+
+func (p StatusPageType) PageTitle() string {
+	if p.Title == "" {
+		return DefaultStatusPageTitle
+	}
+	return p.Title
+}
+
+func (c ComponentType) HasTarget(name string) bool {
+	for _, t := range c.Targets {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Affects reports whether the incident's target, or a target it made
+// unreachable, is part of the component
+func (c ComponentType) Affects(incident IncidentType) bool {
+	if c.HasTarget(incident.Target) {
+		return true
+	}
+	for _, name := range incident.Affected {
+		if c.HasTarget(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisTargetStatusKeyPrefix = "sup:status:"
+
+const (
+	DefaultTargetName = "default"
+	DefaultTimeout    = 20
+	DefaultInterval   = 60 // for targets without an interval when PingFreq isn't set either
+	MinInterval       = 10
+)
+
+type TargetType struct {
+	Name     string
+	URL      string
+	Interval int // seconds between checks. falls back to PingFreq, then DefaultInterval
+	Timeout  int // seconds before a check is given up on
+
+	Retries    int // extra attempts made right away before a check counts as failed
+	RetryDelay int // seconds between those attempts
+
+	Tags    []string
+	Parents []string // names of targets this one depends on, like the load balancer in front of it
+
+	Quorum int // how many locations must see the target down for it to count as down. defaults to 1
+
+	SLOs []SLOType
+
+	Schedule    string // cron expression. overrides Interval when set
+	ActiveHours string // e.g. "09:00-17:00". checks are paused outside these hours
+	ActiveDays  string // e.g. "Mon-Fri". defaults to every day
+	Timezone    string // IANA name used for Schedule and ActiveHours. defaults to local time
+}
+
+const (
+	StateUp          = "up"
+	StateDown        = "down"
+	StateUnreachable = "unreachable" // down, but only because a parent is down
+)
+
+type TargetStatusType struct {
+	LastStatus       int
+	LastRunAt        time.Time
+	NumErrors        int
+	State            string
+	UnreachableVia   string // the down parent that made this target unreachable
+	Alerted          bool   // whether people were told about the current outage
+	RecentStates     string // U or D for each recent check, oldest first. used for flap detection
+	Flapping         bool
+	LocationsMissing bool      // fewer probe locations are reporting than the quorum needs
+	BurnAlerts       []string  // SLO burn-rate alerts currently firing, as "slo label: alert name"
+	IncidentID       string    // the open incident, if the target is down
+	DownSince        time.Time // when the current run of failed checks started
+	FirstError       string    // what the first of them failed with
+}
+
+// IsFailing reports whether the target was down or unreachable on its last check
+func (s TargetStatusType) IsFailing() bool {
+	return s.State == StateDown || s.State == StateUnreachable
+}
+
+// AllTargets returns every target that should be checked, with defaults filled
+// in. The top-level URL is still supported and is checked as the "default" target.
+func (c ConfigType) AllTargets() []TargetType {
+	targets := []TargetType{}
+	if c.URL != "" {
+		targets = append(targets, TargetType{Name: DefaultTargetName, URL: c.URL})
+	}
+	for _, t := range c.Targets {
+		if t.URL == "" {
+			continue
+		}
+		targets = append(targets, t)
+	}
+
+	for i := range targets {
+		t := &targets[i]
+		if t.Name == "" {
+			t.Name = t.URL
+		}
+		if t.Interval == 0 {
+			t.Interval = c.PingFreq
+		}
+		if t.Interval == 0 {
+			t.Interval = DefaultInterval
+		}
+		if t.Interval < MinInterval {
+			t.Interval = MinInterval
+		}
+		if t.Timeout <= 0 {
+			t.Timeout = DefaultTimeout
+		}
+		if t.Quorum < 1 {
+			t.Quorum = 1
+		}
+	}
+	return targets
+}
+
+func (t TargetType) IntervalDuration() time.Duration {
+	return time.Duration(t.Interval) * time.Second
+}
+
+func (t TargetType) TimeoutDuration() time.Duration {
+	return time.Duration(t.Timeout) * time.Second
+}
+
+func (t TargetType) RetryDelayDuration() time.Duration {
+	return time.Duration(t.RetryDelay) * time.Second
+}
+
+func (t TargetType) HasTag(tag string) bool {
+	for _, t := range t.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (t TargetType) Location() *time.Location {
+	return loadLocation(t.Timezone)
+}
+
+// loadLocation returns the named timezone, or local time if there isn't one
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		logOnce("timezone %s: %s. using local time", name, err)
+		return time.Local
+	}
+	return loc
+}
+
+// problems already logged by logOnce
+var loggedOnce sync.Map
+
+// logOnce logs a problem with the config the first time it is seen. Configs
+// saved before validation may still have them, and they are run into on
+// every check.
+func logOnce(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if _, seen := loggedOnce.LoadOrStore(message, true); !seen {
+		log.Println(message)
+	}
+}
+
+// NextRun returns when the target should next be checked after the given time,
+// following its cron schedule if it has one and its interval otherwise
+func (t TargetType) NextRun(after time.Time) time.Time {
+	if t.Schedule != "" {
+		cron, err := ParseCron(t.Schedule)
+		if err != nil {
+			logOnce("%s: %s. checking every %s instead", t.Name, err, t.IntervalDuration())
+		} else if next := cron.Next(after.In(t.Location())); !next.IsZero() {
+			return next
+		}
+	}
+	return after.Add(t.IntervalDuration())
+}
+
+// IsActive reports whether the target's active hours include the given time.
+// Targets without active hours are always active.
+func (t TargetType) IsActive(now time.Time) bool {
+	if t.ActiveHours == "" && t.ActiveDays == "" {
+		return true
+	}
+	window, err := ParseActiveWindow(t.ActiveHours, t.ActiveDays)
+	if err != nil {
+		logOnce("%s: %s. checking at all hours instead", t.Name, err)
+		return true
+	}
+	return window.Contains(now.In(t.Location()))
+}
+
+func GetTargetStatus(name string) TargetStatusType {
+	var status TargetStatusType
+	getJSON(redisTargetStatusKeyPrefix+name, &status)
+	return status
+}
+
+// SetTargetStatus saves the status and reports whether it was accepted. It is
+// rejected when this worker has been fenced off by a newer leader.
+func SetTargetStatus(name string, status TargetStatusType) bool {
+	data, err := json.Marshal(status)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	return fencedSet(c, redisTargetStatusKeyPrefix+name, data)
+}
+
+func getJSON(key string, v interface{}) bool {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	data, err := redis.Bytes(c.Do("GET", key))
+	if err == redis.ErrNil {
+		return false
+	}
+	check(err)
+
+	json.Unmarshal(data, v)
+	return true
+}
+
+func setJSON(key string, v interface{}) {
+	data, err := json.Marshal(v)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("SET", key, data)
+	check(err)
+}
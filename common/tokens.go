@@ -0,0 +1,190 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var redisTokensKey = "sup:tokens"
+
+// API token scopes. Each scope can do everything the ones before it can.
+const (
+	ScopeRead    = "read"    // look at status, history and config
+	ScopeSilence = "silence" // also turn calls on and off, add maintenance and ack incidents
+	ScopeAdmin   = "admin"   // everything
+)
+
+var Scopes = []string{ScopeRead, ScopeSilence, ScopeAdmin}
+
+// tokens look like sup_<id>_<secret>
+const tokenPrefix = "sup_"
+
+// LastUsedAt is only written this often, so busy scripts don't write on every request
+const tokenLastUsedResolution = time.Minute
+
+// APITokenType is a named bearer token. Only a hash of its secret is stored.
+type APITokenType struct {
+	ID         string
+	Name       string
+	Scope      string
+	Hash       string // sha256 of the secret, hex encoded
+	CreatedAt  time.Time
+	ExpiresAt  time.Time // zero if the token never expires
+	LastUsedAt time.Time
+}
+
+func scopeRank(scope string) int {
+	for i, s := range Scopes {
+		if s == scope {
+			return i
+		}
+	}
+	return -1
+}
+
+func ValidScope(scope string) bool {
+	return scopeRank(scope) >= 0
+}
+
+// ScopeAllows reports whether having scope `have` is enough for `need`
+func ScopeAllows(have, need string) bool {
+	return scopeRank(have) >= 0 && scopeRank(have) >= scopeRank(need)
+}
+
+// Allows reports whether the token's scope covers the given scope
+func (t APITokenType) Allows(scope string) bool {
+	return ScopeAllows(t.Scope, scope)
+}
+
+func (t APITokenType) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken saves a new token and returns it along with the full token
+// string, which is the only time the secret is available
+func CreateToken(name, scope string, expiresAt time.Time) (APITokenType, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return APITokenType{}, "", fmt.Errorf("tokens need a name")
+	}
+	if !ValidScope(scope) {
+		return APITokenType{}, "", fmt.Errorf("scope must be one of %s", strings.Join(Scopes, ", "))
+	}
+
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	check(err)
+	secret := hex.EncodeToString(b)
+
+	t := APITokenType{
+		ID:        newID(),
+		Name:      strings.TrimSpace(name),
+		Scope:     scope,
+		Hash:      hashSecret(secret),
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	saveToken(t)
+	return t, tokenPrefix + t.ID + "_" + secret, nil
+}
+
+func saveToken(t APITokenType) {
+	data, err := json.Marshal(t)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("HSET", redisTokensKey, t.ID, data)
+	check(err)
+}
+
+func getToken(id string) (APITokenType, bool) {
+	var t APITokenType
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	data, err := redis.Bytes(c.Do("HGET", redisTokensKey, id))
+	if err == redis.ErrNil {
+		return t, false
+	}
+	check(err)
+	return t, json.Unmarshal(data, &t) == nil
+}
+
+// GetTokens returns all tokens, oldest first
+func GetTokens() []APITokenType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisTokensKey))
+	check(err)
+
+	tokens := []APITokenType{}
+	for _, data := range values {
+		var t APITokenType
+		if json.Unmarshal([]byte(data), &t) == nil {
+			tokens = append(tokens, t)
+		}
+	}
+	sort.Sort(byCreatedAt(tokens))
+	return tokens
+}
+
+// RevokeToken deletes the token. It returns false if there was no such token.
+func RevokeToken(id string) bool {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	n, err := redis.Int(c.Do("HDEL", redisTokensKey, id))
+	check(err)
+	return n > 0
+}
+
+// AuthenticateToken checks a full token string and returns the token if it
+// is valid and hasn't expired. It records when the token was used.
+func AuthenticateToken(token string) (APITokenType, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(token, tokenPrefix), "_", 2)
+	if !strings.HasPrefix(token, tokenPrefix) || len(parts) != 2 {
+		return APITokenType{}, false
+	}
+
+	t, ok := getToken(parts[0])
+	if !ok || subtle.ConstantTimeCompare([]byte(hashSecret(parts[1])), []byte(t.Hash)) != 1 {
+		return APITokenType{}, false
+	}
+
+	now := time.Now()
+	if t.IsExpired(now) {
+		return APITokenType{}, false
+	}
+	if now.Sub(t.LastUsedAt) > tokenLastUsedResolution {
+		t.LastUsedAt = now
+		saveToken(t)
+	}
+	return t, true
+}
+
+type byCreatedAt []APITokenType
+
+func (s byCreatedAt) Len() int           { return len(s) }
+func (s byCreatedAt) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byCreatedAt) Less(i, j int) bool { return s[i].CreatedAt.Before(s[j].CreatedAt) }
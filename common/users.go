@@ -0,0 +1,505 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var redisUsersKey = "sup:users"
+var redisUserSubjectsKey = "sup:users:subjects" // SSO issuer and subject -> username
+var redisSessionKeyPrefix = "sup:session:"
+var redisUserSessionsKeyPrefix = "sup:sessions:" // set of a user's session ids
+var redisLoginStateKeyPrefix = "sup:loginstate:"
+
+// user roles. each maps onto the token scope with the same powers
+const (
+	RoleViewer    = "viewer"    // look at everything
+	RoleResponder = "responder" // also acknowledge incidents, add maintenance and turn calls on and off
+	RoleAdmin     = "admin"     // also edit config, users and tokens
+)
+
+var Roles = []string{RoleViewer, RoleResponder, RoleAdmin}
+
+var roleScopes = map[string]string{
+	RoleViewer:    ScopeRead,
+	RoleResponder: ScopeSilence,
+	RoleAdmin:     ScopeAdmin,
+}
+
+// sessions last this long after the last request
+const SessionTTL = 24 * time.Hour
+
+// how long someone has to finish signing in at the identity provider
+const loginStateTTL = 10 * time.Minute
+
+const minPasswordLength = 8
+
+type UserType struct {
+	Username     string
+	PasswordHash string `json:",omitempty"` // bcrypt. empty for users who can only sign in through SSO
+	Role         string
+	CreatedAt    time.Time
+
+	// bcrypt of the --web_auth password the admin that flag creates was last
+	// set from
+	BootstrapHash string `json:",omitempty"`
+
+	// SSO users are identified by issuer and subject. The name the identity
+	// provider gives them is only for display, since it isn't unique or stable.
+	Subject     string `json:",omitempty"`
+	DisplayName string `json:",omitempty"`
+}
+
+// SessionType is a signed in browser. The user's role is looked up on every
+// request, so role changes and deletions take effect straight away.
+type SessionType struct {
+	ID        string
+	Username  string
+	CreatedAt time.Time
+}
+
+// a real hash to compare against when the user doesn't exist, so unknown
+// users take as long to reject as wrong passwords
+var dummyHash []byte
+var dummyHashOnce sync.Once
+
+func ValidRole(role string) bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+// RoleScope is the token scope that has the same powers as the role
+func RoleScope(role string) string {
+	return roleScopes[role]
+}
+
+func checkPassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("passwords must be at least %d characters", minPasswordLength)
+	}
+	return nil
+}
+
+func hashPassword(password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	check(err)
+	return string(hash)
+}
+
+// CreateUser adds a user. It fails if the username is taken.
+func CreateUser(username, password, role string) (UserType, error) {
+	if err := checkPassword(password); err != nil {
+		return UserType{}, err
+	}
+	return createUser(UserType{Username: username, PasswordHash: hashPassword(password), Role: role})
+}
+
+// BootstrapAdmin creates an admin with the given password if there is no user
+// with that name yet. It is how the old single --web_auth login carries over.
+// When the password passed in changes, the admin's password is changed to
+// match, so changing --web_auth still rotates it; a password changed on the
+// users page is kept until then. It returns what it did, for logging, or ""
+// if nothing changed. The password isn't length checked, since it was chosen
+// before there was a rule.
+func BootstrapAdmin(username, password string) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("the bootstrap admin needs a password")
+	}
+
+	u, ok := GetUser(username)
+	if !ok {
+		hash := hashPassword(password)
+		_, err := createUser(UserType{Username: username, PasswordHash: hash, BootstrapHash: hash, Role: RoleAdmin})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("created admin user %s", username), nil
+	}
+
+	if u.BootstrapHash == "" {
+		if u.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil {
+			return "", nil
+		}
+		return fmt.Sprintf("--web_auth is ignored because %s already exists and wasn't created from it. change their password on the users page or with sup users passwd", username), nil
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.BootstrapHash), []byte(password)) == nil {
+		return "", nil
+	}
+
+	u.PasswordHash = hashPassword(password)
+	u.BootstrapHash = u.PasswordHash
+	saveUser(u)
+	DeleteUserSessions(username)
+	return fmt.Sprintf("--web_auth changed, so %s's password was changed to match", username), nil
+}
+
+// createUser adds the user. Its password hash is empty for users who only
+// sign in through SSO.
+func createUser(u UserType) (UserType, error) {
+	u.Username = strings.TrimSpace(u.Username)
+	if u.Username == "" {
+		return UserType{}, fmt.Errorf("users need a username")
+	}
+	if !ValidRole(u.Role) {
+		return UserType{}, fmt.Errorf("role must be one of %s", strings.Join(Roles, ", "))
+	}
+
+	u.CreatedAt = time.Now()
+	data, err := json.Marshal(u)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	added, err := redis.Int(c.Do("HSETNX", redisUsersKey, u.Username, data))
+	check(err)
+	if added == 0 {
+		return UserType{}, fmt.Errorf("user %s already exists", u.Username)
+	}
+	return u, nil
+}
+
+func saveUser(u UserType) {
+	data, err := json.Marshal(u)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("HSET", redisUsersKey, u.Username, data)
+	check(err)
+}
+
+// GetUser loads a user. The bool is false if there is no such user.
+func GetUser(username string) (UserType, bool) {
+	var u UserType
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	data, err := redis.Bytes(c.Do("HGET", redisUsersKey, username))
+	if err == redis.ErrNil {
+		return u, false
+	}
+	check(err)
+	return u, json.Unmarshal(data, &u) == nil
+}
+
+// GetUsers returns all users, sorted by username
+func GetUsers() []UserType {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", redisUsersKey))
+	check(err)
+
+	users := []UserType{}
+	for _, data := range values {
+		var u UserType
+		if json.Unmarshal([]byte(data), &u) == nil {
+			users = append(users, u)
+		}
+	}
+	sort.Sort(byUsername(users))
+	return users
+}
+
+func HasUsers() bool {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	n, err := redis.Int(c.Do("HLEN", redisUsersKey))
+	check(err)
+	return n > 0
+}
+
+func SetUserRole(username, role string) error {
+	if !ValidRole(role) {
+		return fmt.Errorf("role must be one of %s", strings.Join(Roles, ", "))
+	}
+	u, ok := GetUser(username)
+	if !ok {
+		return fmt.Errorf("no such user")
+	}
+	u.Role = role
+	saveUser(u)
+	return nil
+}
+
+// SetUserPassword changes the password and signs the user out everywhere
+func SetUserPassword(username, password string) error {
+	u, ok := GetUser(username)
+	if !ok {
+		return fmt.Errorf("no such user")
+	}
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+	u.PasswordHash = hashPassword(password)
+	saveUser(u)
+	// a stolen session shouldn't outlive the old password
+	DeleteUserSessions(username)
+	return nil
+}
+
+// DeleteUser removes the user and ends their sessions
+func DeleteUser(username string) bool {
+	u, _ := GetUser(username)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	if u.Subject != "" {
+		_, err = c.Do("HDEL", redisUserSubjectsKey, u.Subject)
+		check(err)
+	}
+	n, err := redis.Int(c.Do("HDEL", redisUsersKey, username))
+	check(err)
+	DeleteUserSessions(username)
+	return n > 0
+}
+
+// AuthenticateUser checks a username and password
+func AuthenticateUser(username, password string) (UserType, bool) {
+	u, ok := GetUser(username)
+	if !ok || u.PasswordHash == "" {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("sup"), bcrypt.DefaultCost)
+		})
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return UserType{}, false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return UserType{}, false
+	}
+	return u, true
+}
+
+// CreateSession starts a session for the user and returns it
+func CreateSession(username string) SessionType {
+	s := SessionType{ID: newID() + newID(), Username: username, CreatedAt: time.Now()}
+	data, err := json.Marshal(s)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	ttl := int64(SessionTTL / time.Millisecond)
+	c.Send("MULTI")
+	c.Send("SET", redisSessionKeyPrefix+s.ID, data, "PX", ttl)
+	c.Send("SADD", redisUserSessionsKeyPrefix+username, s.ID)
+	c.Send("PEXPIRE", redisUserSessionsKeyPrefix+username, ttl)
+	_, err = c.Do("EXEC")
+	check(err)
+	return s
+}
+
+// GetSession loads a session and extends it. The bool is false if the session
+// has expired or doesn't exist.
+func GetSession(id string) (SessionType, bool) {
+	var s SessionType
+	if id == "" {
+		return s, false
+	}
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	key := redisSessionKeyPrefix + id
+	data, err := redis.Bytes(c.Do("GET", key))
+	if err == redis.ErrNil {
+		return s, false
+	}
+	check(err)
+	if json.Unmarshal(data, &s) != nil {
+		return s, false
+	}
+
+	ttl := int64(SessionTTL / time.Millisecond)
+	c.Send("MULTI")
+	c.Send("PEXPIRE", key, ttl)
+	c.Send("PEXPIRE", redisUserSessionsKeyPrefix+s.Username, ttl)
+	_, err = c.Do("EXEC")
+	check(err)
+	return s, true
+}
+
+func DeleteSession(id string) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	key := redisSessionKeyPrefix + id
+	data, err := redis.Bytes(c.Do("GET", key))
+	if err == redis.ErrNil {
+		return
+	}
+	check(err)
+	var s SessionType
+	json.Unmarshal(data, &s)
+
+	c.Send("MULTI")
+	c.Send("DEL", key)
+	c.Send("SREM", redisUserSessionsKeyPrefix+s.Username, id)
+	_, err = c.Do("EXEC")
+	check(err)
+}
+
+// DeleteUserSessions signs the user out everywhere
+func DeleteUserSessions(username string) {
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	setKey := redisUserSessionsKeyPrefix + username
+	ids, err := redis.Strings(c.Do("SMEMBERS", setKey))
+	check(err)
+
+	c.Send("MULTI")
+	for _, id := range ids {
+		c.Send("DEL", redisSessionKeyPrefix+id)
+	}
+	c.Send("DEL", setKey)
+	_, err = c.Do("EXEC")
+	check(err)
+}
+
+// SSOUser returns the user for an identity provider account, creating them if
+// needed. Accounts are told apart by issuer and subject. name, e.g. their
+// preferred_username, is only shown, and becomes the username if it is free;
+// otherwise a short hash of the subject is added to it. So local users can't be
+// taken over, and two accounts with the same name stay two users. The role is
+// set from the identity provider on every sign in.
+func SSOUser(issuer, subject, name, role string) (UserType, error) {
+	if !ValidRole(role) {
+		return UserType{}, fmt.Errorf("role must be one of %s", strings.Join(Roles, ", "))
+	}
+	if subject == "" {
+		return UserType{}, fmt.Errorf("the identity provider didn't say who you are")
+	}
+	key := issuer + " " + subject
+	if name == "" {
+		name = subject
+	}
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	username, err := redis.String(c.Do("HGET", redisUserSubjectsKey, key))
+	if err != redis.ErrNil {
+		check(err)
+		if u, ok := GetUser(username); ok && u.Subject == key {
+			if u.Role != role || u.DisplayName != name {
+				u.Role, u.DisplayName = role, name
+				saveUser(u)
+			}
+			return u, nil
+		}
+		// the user was deleted
+		_, err = c.Do("HDEL", redisUserSubjectsKey, key)
+		check(err)
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	for _, username := range []string{name, name + "-" + hex.EncodeToString(sum[:3])} {
+		u, err := createUser(UserType{Username: username, Role: role, Subject: key, DisplayName: name})
+		if err == nil {
+			return claimSubject(c, u)
+		}
+	}
+	return UserType{}, fmt.Errorf("couldn't create a user for %s", name)
+}
+
+// claimSubject records that u is the user for its SSO subject. If someone
+// else signing in as the same account got there first, u is dropped and
+// theirs is returned.
+func claimSubject(c redis.Conn, u UserType) (UserType, error) {
+	added, err := redis.Int(c.Do("HSETNX", redisUserSubjectsKey, u.Subject, u.Username))
+	check(err)
+	if added == 1 {
+		return u, nil
+	}
+
+	username, err := redis.String(c.Do("HGET", redisUserSubjectsKey, u.Subject))
+	check(err)
+	if username != u.Username {
+		_, err = c.Do("HDEL", redisUsersKey, u.Username)
+		check(err)
+	}
+	winner, ok := GetUser(username)
+	if !ok {
+		return UserType{}, fmt.Errorf("no such user")
+	}
+	return winner, nil
+}
+
+// LoginStateType is what the server remembers while someone is away signing
+// in at the identity provider
+type LoginStateType struct {
+	Verifier string // PKCE code verifier
+	Nonce    string
+	Next     string // where to go after signing in
+}
+
+// SaveLoginState stores the state under the given id for a few minutes
+func SaveLoginState(id string, s LoginStateType) {
+	data, err := json.Marshal(s)
+	check(err)
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	_, err = c.Do("SET", redisLoginStateKeyPrefix+id, data, "PX", int64(loginStateTTL/time.Millisecond))
+	check(err)
+}
+
+// TakeLoginState returns the state and deletes it, so it can only be used once
+func TakeLoginState(id string) (LoginStateType, bool) {
+	var s LoginStateType
+	if id == "" {
+		return s, false
+	}
+
+	c, err := getRedis()
+	check(err)
+	defer c.Close()
+
+	key := redisLoginStateKeyPrefix + id
+	c.Send("MULTI")
+	c.Send("GET", key)
+	c.Send("DEL", key)
+	values, err := redis.Values(c.Do("EXEC"))
+	check(err)
+
+	data, err := redis.Bytes(values[0], nil)
+	if err == redis.ErrNil {
+		return s, false
+	}
+	check(err)
+	return s, json.Unmarshal(data, &s) == nil
+}
+
+type byUsername []UserType
+
+func (s byUsername) Len() int           { return len(s) }
+func (s byUsername) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byUsername) Less(i, j int) bool { return s[i].Username < s[j].Username }
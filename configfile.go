@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+// how often the config file is checked for changes
+const configFilePollFreq = 5 * time.Second
+
+// loadConfigFile loads the config file into redis, recording what changed in
+// the audit log
+func loadConfigFile() error {
+	diff, err := common.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+	if diff != "" {
+		path, _ := common.ConfigFile()
+		log.Printf("config loaded from %s\n", path)
+		common.Audit(common.AuditEventType{Actor: "file:" + path, Action: "config.load", Diff: diff})
+	}
+	return nil
+}
+
+// watchConfigFile reloads the config file on SIGHUP and whenever it changes.
+// A file that fails to load is logged and the config is left as it was.
+func watchConfigFile() {
+	path, _ := common.ConfigFile()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	last := fileStamp(path)
+	ticker := time.NewTicker(configFilePollFreq)
+	for {
+		select {
+		case <-hup:
+			log.Printf("got SIGHUP, reloading %s\n", path)
+		case <-ticker.C:
+			stamp := fileStamp(path)
+			if stamp == last {
+				continue
+			}
+			last = stamp
+		}
+		if err := loadConfigFile(); err != nil {
+			log.Printf("config file not loaded, keeping the current config: %s\n", err)
+		}
+	}
+}
+
+// fileStamp changes when the file does. Stat follows symlinks, so files
+// swapped in by symlink, like Kubernetes config maps, are noticed too.
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return info.ModTime().String() + " " + strconv.FormatInt(info.Size(), 10)
+}
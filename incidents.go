@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/topscore/sup/common"
+)
+
+// notify posts to Hipchat and records it on the incident, if there is one
+func notify(incidentID, message, color string) {
+	common.HipchatNotice(message, color)
+	if incidentID != "" {
+		common.AddIncidentEvent(incidentID, common.IncidentEventType{
+			Kind: common.EventNotified,
+			Text: "hipchat: " + message,
+		})
+	}
+}
+
+// callForIncident calls the dev team and records who was called on the incident
+func callForIncident(incidentID, message string) {
+	for _, num := range callDevTeam(message) {
+		if incidentID != "" {
+			common.AddIncidentEvent(incidentID, common.IncidentEventType{
+				Kind: common.EventCalled,
+				Text: num,
+			})
+		}
+	}
+}
+
+func isAcknowledged(incidentID string) bool {
+	incident, ok := common.GetIncident(incidentID)
+	return ok && incident.AckedBy != ""
+}
+
+func describeResult(result common.CheckResultType) string {
+	if result.Error != "" {
+		return result.Error
+	}
+	return fmt.Sprintf("status %d", result.StatusCode)
+}
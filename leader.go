@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+// how long the leader lease lasts without being renewed
+const leaseTTL = 15 * time.Second
+
+// how often the lease is renewed (or, when not leader, how often we try to take it)
+const leaseRenewFreq = 5 * time.Second
+
+// leaderLease keeps this worker's claim on the Redis leader lease, so that when
+// several workers run only one of them pings and calls people
+type leaderLease struct {
+	id string
+
+	mu       sync.Mutex
+	held     bool
+	token    int64
+	expireAt time.Time
+}
+
+func newLeaderLease() *leaderLease {
+	host, _ := os.Hostname()
+	if dyno := os.Getenv("DYNO"); dyno != "" {
+		host = dyno
+	}
+	return &leaderLease{id: fmt.Sprintf("%s:%d", host, os.Getpid())}
+}
+
+func (l *leaderLease) Run() {
+	go l.releaseOnExit()
+	for {
+		l.renew()
+		time.Sleep(leaseRenewFreq)
+	}
+}
+
+// Held reports whether we are leader. A lease we failed to renew is treated as
+// lost a little before it actually expires in Redis.
+func (l *leaderLease) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held && time.Now().Before(l.expireAt)
+}
+
+func (l *leaderLease) renew() {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Printf("could not renew leader lease: %v\n", e)
+		}
+	}()
+
+	start := time.Now()
+	token, ok := common.AcquireLease(l.id, leaseTTL)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ok && (!l.held || token != l.token) {
+		log.Printf("%s is now the leader (fence token %d)\n", l.id, token)
+		common.SetFenceToken(token)
+	} else if !ok && l.held {
+		log.Printf("%s lost the leader lease\n", l.id)
+	}
+
+	l.held = ok
+	l.token = token
+	l.expireAt = start.Add(leaseTTL - leaseRenewFreq)
+}
+
+// releaseOnExit gives the lease up on shutdown so another worker can take over
+// right away instead of waiting for it to expire
+func (l *leaderLease) releaseOnExit() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	<-sigs
+
+	l.mu.Lock()
+	if l.held {
+		common.ReleaseLease(l.id)
+		log.Printf("%s released the leader lease\n", l.id)
+	}
+	os.Exit(0)
+}
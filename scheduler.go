@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+// how often the scheduler looks for checks that are due
+const schedulerTick = time.Second
+
+// max random delay added to cron-scheduled checks
+const cronJitter = 10 * time.Second
+
+// how often the target list is reloaded from the config
+const configReloadFreq = 30 * time.Second
+
+// scheduler runs each target on its own interval, using at most `workers`
+// checks at once. A target whose previous check is still running is skipped
+// rather than started a second time. Only the worker holding the leader lease
+// runs checks; the others wait to take over.
+type scheduler struct {
+	slots chan struct{}
+	lease *leaderLease // nil if this process doesn't need to be leader to run checks
+
+	loadTargetsFunc func() []common.TargetType
+	checkFunc       func(common.TargetType)
+
+	mu      sync.Mutex
+	targets map[string]common.TargetType
+	nextRun map[string]time.Time
+	running map[string]bool
+}
+
+func newScheduler(workers int, lease *leaderLease, loadTargets func() []common.TargetType, check func(common.TargetType)) *scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &scheduler{
+		slots:           make(chan struct{}, workers),
+		lease:           lease,
+		loadTargetsFunc: loadTargets,
+		checkFunc:       check,
+		targets:         map[string]common.TargetType{},
+		nextRun:         map[string]time.Time{},
+		running:         map[string]bool{},
+	}
+}
+
+func (s *scheduler) Run() {
+	s.loadTargets()
+	reload := time.NewTicker(configReloadFreq)
+	tick := time.NewTicker(schedulerTick)
+	defer reload.Stop()
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-reload.C:
+			s.loadTargets()
+		case now := <-tick.C:
+			s.runDue(now)
+		}
+	}
+}
+
+// loadTargets syncs the scheduled targets with the config. New targets get a
+// random start offset within their interval so they don't all fire together.
+// If the target list can't be loaded (nil), the current one is kept.
+func (s *scheduler) loadTargets() {
+	targets := s.loadTargetsFunc()
+	if targets == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, t := range targets {
+		seen[t.Name] = true
+		old, exists := s.targets[t.Name]
+		s.targets[t.Name] = t
+		if !exists || old.Interval != t.Interval || old.Schedule != t.Schedule || old.Timezone != t.Timezone {
+			s.nextRun[t.Name] = firstRun(t, time.Now())
+			if t.Schedule != "" {
+				fmt.Printf("Pinging %s on schedule %q\n", t.URL, t.Schedule)
+			} else {
+				fmt.Printf("Pinging %s every %d seconds\n", t.URL, t.Interval)
+			}
+		}
+	}
+
+	for name := range s.targets {
+		if !seen[name] {
+			delete(s.targets, name)
+			delete(s.nextRun, name)
+		}
+	}
+}
+
+func (s *scheduler) runDue(now time.Time) {
+	if s.lease != nil && !s.lease.Held() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, t := range s.targets {
+		if now.Before(s.nextRun[name]) {
+			continue
+		}
+
+		s.nextRun[name] = nextRun(t, now)
+
+		if !t.IsActive(now) {
+			continue
+		}
+
+		if s.running[name] {
+			log.Printf("%s: previous check still running, skipping\n", name)
+			continue
+		}
+
+		s.running[name] = true
+		go s.runCheck(t)
+	}
+}
+
+func (s *scheduler) runCheck(t common.TargetType) {
+	s.slots <- struct{}{}
+	defer func() {
+		<-s.slots
+		s.mu.Lock()
+		delete(s.running, t.Name)
+		s.mu.Unlock()
+
+		if e := recover(); e != nil {
+			log.Printf("%s: check failed: %v\n", t.Name, e)
+		}
+	}()
+
+	s.checkFunc(t)
+}
+
+// firstRun spreads new interval targets across their whole interval. Cron
+// targets keep to their schedule, only nudged by a few seconds.
+func firstRun(t common.TargetType, now time.Time) time.Time {
+	if t.Schedule != "" {
+		return t.NextRun(now).Add(jitter(cronJitter))
+	}
+	return now.Add(jitter(t.IntervalDuration()))
+}
+
+func nextRun(t common.TargetType, now time.Time) time.Time {
+	if t.Schedule != "" {
+		return t.NextRun(now).Add(jitter(cronJitter))
+	}
+	return t.NextRun(now).Add(jitter(t.IntervalDuration() / 10))
+}
+
+// jitter returns a random duration in [0, max)
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
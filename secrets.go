@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/topscore/sup/common"
+)
+
+// secretsCommand manages the keys config secrets are encrypted with
+var secretsCommand = cli.Command{
+	Name:  "secrets",
+	Usage: "manage encryption of secrets in the config",
+	Subcommands: []cli.Command{
+		{
+			Name:  "genkey",
+			Usage: "print a new random key for --secret_key",
+			Action: func(c *cli.Context) {
+				fmt.Println(common.NewSecretKey())
+			},
+		},
+		{
+			Name:  "rotate",
+			Usage: "encrypt all secrets with the first key in --secret_key, after which the other keys can be removed",
+			Action: func(c *cli.Context) {
+				exitOnError(common.RotateSecrets())
+				auditCLI("secrets.rotate", "")
+				fmt.Println("secrets are now encrypted with the first key")
+			},
+		},
+	},
+}
+
+// secretKeys reads the keys from --secret_key and --secret_key_file. The
+// current key comes first, then old ones still needed to decrypt.
+func secretKeys(c *cli.Context) ([]string, error) {
+	keys := splitList(c.GlobalString("secret_key"))
+	if path := c.GlobalString("secret_key_file"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				keys = append(keys, line)
+			}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+// evaluateSLOs checks the target's burn-rate alerts against recent history plus
+// the latest result, and updates status.BurnAlerts. It returns the alerts that
+// just started firing and the ones that just stopped.
+func evaluateSLOs(target common.TargetType, result common.CheckResultType, status *common.TargetStatusType) ([]string, []string) {
+	if len(target.SLOs) == 0 {
+		status.BurnAlerts = nil
+		return nil, nil
+	}
+
+	longest := time.Duration(0)
+	for _, alert := range common.BurnAlerts {
+		if alert.Long > longest {
+			longest = alert.Long
+		}
+	}
+	results := append(common.GetHistoryRange(target.Name, result.At.Add(-longest), result.At), result)
+
+	wasFiring := map[string]bool{}
+	for _, name := range status.BurnAlerts {
+		wasFiring[name] = true
+	}
+
+	firing := []string{}
+	started := []string{}
+	for _, slo := range target.SLOs {
+		for _, alert := range common.BurnAlerts {
+			if !slo.IsBurning(alert, results, result.At, failuresBeforeCall) {
+				continue
+			}
+			name := slo.Label() + ": " + alert.Name
+			firing = append(firing, name)
+			if !wasFiring[name] {
+				started = append(started, name)
+			}
+			delete(wasFiring, name)
+		}
+	}
+
+	resolved := []string{}
+	for name := range wasFiring {
+		resolved = append(resolved, name)
+	}
+
+	status.BurnAlerts = firing
+	return started, resolved
+}
@@ -5,49 +5,57 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/topscore/sup/common"
 	"github.com/topscore/sup/webserver"
 
+	"github.com/andybons/hipchat"
 	"github.com/codegangsta/cli"
 	"github.com/sfreiberg/gotwilio"
 )
 
+// how many failed checks in a row before people get called
+const failuresBeforeCall = 5
+
+// name of the location this process checks from
+var location string
+
 func check(e error) {
 	if e != nil {
 		panic(e)
 	}
 }
 
-func callDevTeam() {
+// callDevTeam calls everyone on call and returns the numbers that were called
+func callDevTeam(message string) []string {
 	config := common.GetConfig()
 	twilio := gotwilio.NewTwilioClient(config.TwilioSID, config.TwilioAuthToken)
-	messageURL := "http://twimlets.com/message?Message%5B0%5D=SITE%20IS%20DOWN!"
+	messageURL := "http://twimlets.com/message?Message%5B0%5D=" + url.QueryEscape(message)
 	callbackParams := gotwilio.NewCallbackParameters(messageURL)
+	called := []string{}
 
 	for _, num := range config.Phones {
 		fmt.Printf("!!! Calling %s\n", num)
 
 		_, tException, err := twilio.CallWithUrlCallbacks(config.TwilioCallFrom, num, callbackParams)
 		if tException != nil {
+			common.CountNotification(common.ChannelPhone, fmt.Errorf("%+v", tException))
 			panic(fmt.Sprintf("Twilio error: %+v\n", tException))
 		}
+		common.CountNotification(common.ChannelPhone, err)
 		check(err)
+		called = append(called, num)
 	}
+	return called
 }
 
-func pingSite(c *cli.Context) {
-	simulateDown := c.GlobalBool("down")
-
-	config := common.GetConfig()
-	status := common.GetStatus()
-
-	if config.URL == "" {
-		return
-	}
+func pingSite(target common.TargetType, simulateDown bool) {
+	status := common.GetTargetStatus(target.Name)
 
 	defer func() {
 		if e := recover(); e != nil {
@@ -61,45 +69,252 @@ func pingSite(c *cli.Context) {
 		}
 	}()
 
+	result := confirmedProbe(target, simulateDown)
+	statusCode := result.StatusCode
+	common.SetLocationResult(result)
+
+	downCount, locationCount := countDownLocations(target)
+	// with too few locations reporting the target can't reach its quorum.
+	// the quorum isn't lowered to match, since then one location could page
+	// again, so people are told instead
+	locationsChanged := status.LocationsMissing != (locationCount < target.Quorum)
+	status.LocationsMissing = locationCount < target.Quorum
+	if downCount >= target.Quorum {
+		status.NumErrors++
+		if status.NumErrors == 1 || status.DownSince.IsZero() {
+			status.DownSince = result.At
+			status.FirstError = describeResult(result)
+		}
+		status.State = common.StateDown
+		status.UnreachableVia = downParent(target)
+		if status.UnreachableVia != "" {
+			status.State = common.StateUnreachable
+			log.Printf("%s is unreachable due to parent %s\n", target.Name, status.UnreachableVia)
+		} else {
+			log.Printf("%s is down. Status is %d (%d of %d locations)\n", target.Name, statusCode, downCount, locationCount)
+		}
+	} else {
+		status.NumErrors = 0
+		status.DownSince = time.Time{}
+		status.FirstError = ""
+		status.State = common.StateUp
+		status.UnreachableVia = ""
+	}
+
+	status.LastStatus = statusCode
+	status.LastRunAt = result.At
+	flapChanged := status.RecordState(status.IsFailing())
+	result.Maintenance = common.InMaintenance(target, result.At)
+	result.State = status.State // before evaluateSLOs, which only counts results with a state
+
+	// incidents are only opened once the failure is confirmed, like calls, so
+	// a blip doesn't become one. they stay open while a target flaps, so
+	// bouncing doesn't churn them
+	openedIncident, resolvedIncident := "", ""
+	if status.State == common.StateDown && status.NumErrors >= failuresBeforeCall && status.IncidentID == "" {
+		status.IncidentID = common.NewIncidentID()
+		openedIncident = status.IncidentID
+	} else if status.State == common.StateUp && status.IncidentID != "" && !status.Flapping {
+		resolvedIncident = status.IncidentID
+		status.IncidentID = ""
+	}
+
+	burnStarted, burnResolved := evaluateSLOs(target, result, &status)
+	disabled := common.GetStatus().Disabled
+
+	shouldCall := false
+	announce := ""
+	if !status.Flapping {
+		if status.State == common.StateDown && status.NumErrors >= failuresBeforeCall && !disabled {
+			if result.Maintenance {
+				log.Printf("%s is in maintenance, not calling\n", target.Name)
+			} else if isAcknowledged(status.IncidentID) {
+				log.Printf("%s is down but the incident was acknowledged, not calling\n", target.Name)
+			} else {
+				shouldCall = true
+				if !status.Alerted {
+					announce = fmt.Sprintf("%s is down. Status is %d", target.Name, statusCode)
+				}
+				status.Alerted = true
+			}
+		} else if status.State == common.StateUp && status.Alerted {
+			announce = fmt.Sprintf("%s is back up", target.Name)
+			status.Alerted = false
+		}
+	}
+
+	if !common.SetTargetStatus(target.Name, status) {
+		log.Printf("%s: another worker is leader now, dropping result\n", target.Name)
+		return
+	}
+
+	common.AddCheckResult(result, common.GetConfig().HistoryRetentionFor(target.Name))
+
+	if openedIncident != "" {
+		common.CreateIncident(common.IncidentType{
+			ID:         openedIncident,
+			Target:     target.Name,
+			StartedAt:  status.DownSince,
+			FirstError: status.FirstError,
+		})
+	}
+	if resolvedIncident != "" {
+		common.ResolveIncident(resolvedIncident, result.At)
+	}
+	if status.State == common.StateUnreachable {
+		if parentIncident := common.GetTargetStatus(status.UnreachableVia).IncidentID; parentIncident != "" {
+			common.AddAffectedTarget(parentIncident, target.Name)
+		}
+	}
+
+	incidentID := status.IncidentID
+	if resolvedIncident != "" {
+		incidentID = resolvedIncident
+	}
+
+	if locationsChanged {
+		if status.LocationsMissing {
+			log.Printf("%s: quorum is %d but only %d locations are reporting\n", target.Name, target.Quorum, locationCount)
+			common.HipchatNotice(fmt.Sprintf("%s: probe locations missing. Only %d of the %d locations its quorum needs are reporting, so it can't be seen as down", target.Name, locationCount, target.Quorum), hipchat.ColorYellow)
+		} else {
+			common.HipchatNotice(fmt.Sprintf("%s: enough probe locations are reporting again", target.Name), hipchat.ColorGreen)
+		}
+	}
+	if flapChanged {
+		if status.Flapping {
+			notify(incidentID, fmt.Sprintf("%s is flapping (%.0f%% state changes). Holding notifications until it settles", target.Name, status.FlapScore()), hipchat.ColorYellow)
+		} else {
+			notify(incidentID, fmt.Sprintf("%s is stable again, currently %s", target.Name, status.State), hipchat.ColorGreen)
+		}
+	}
+	if announce != "" {
+		color := hipchat.ColorRed
+		if status.State == common.StateUp {
+			color = hipchat.ColorGreen
+		}
+		notify(incidentID, announce, color)
+	}
+	if shouldCall {
+		callForIncident(incidentID, "SITE IS DOWN!")
+	}
+
+	for _, name := range burnResolved {
+		common.HipchatNotice(fmt.Sprintf("%s SLO %s stopped burning error budget", target.Name, name), hipchat.ColorGreen)
+	}
+	for _, name := range burnStarted {
+		common.HipchatMessage(fmt.Sprintf("%s SLO %s is burning error budget too fast", target.Name, name))
+		if disabled || result.Maintenance {
+			continue
+		}
+		if status.Flapping {
+			log.Printf("%s SLO %s is burning but the target is flapping, not calling\n", target.Name, name)
+			continue
+		}
+		if isAcknowledged(status.IncidentID) {
+			log.Printf("%s SLO %s is burning but the incident was acknowledged, not calling\n", target.Name, name)
+			continue
+		}
+		callForIncident(status.IncidentID, "ERROR BUDGET IS BURNING FOR "+target.Name)
+	}
+}
+
+// downParent returns the parent whose outage explains this target being down,
+// following unreachable parents up to the one that is actually down. A parent
+// that is only unreachable because of this target doesn't count, so a loop in
+// the parents of a config saved before they were checked can't leave every
+// target in it unreachable and nobody called.
+func downParent(target common.TargetType) string {
+	for _, name := range target.Parents {
+		parent := common.GetTargetStatus(name)
+		switch {
+		case parent.State == common.StateDown:
+			return name
+		case parent.State == common.StateUnreachable && parent.UnreachableVia != target.Name:
+			return parent.UnreachableVia
+		}
+	}
+	return ""
+}
+
+// confirmedProbe probes the target, retrying a failed probe to make sure it
+// wasn't a blip
+func confirmedProbe(target common.TargetType, simulateDown bool) common.CheckResultType {
+	result := probe(target, simulateDown)
+	for i := 0; !result.Up && i < target.Retries; i++ {
+		log.Printf("%s looks down (status %d), retrying in %d seconds\n", target.Name, result.StatusCode, target.RetryDelay)
+		time.Sleep(target.RetryDelayDuration())
+		result = probe(target, simulateDown)
+	}
+	return result
+}
+
+// countDownLocations returns how many locations recently saw the target down,
+// out of how many reported at all. Results older than a few intervals are ignored.
+func countDownLocations(target common.TargetType) (int, int) {
+	staleAt := time.Now().Add(-3 * (target.IntervalDuration() + target.TimeoutDuration()))
+	down, total := 0, 0
+	for _, r := range common.GetLocationResults(target.Name) {
+		if r.At.Before(staleAt) {
+			continue
+		}
+		total++
+		if !r.Up {
+			down++
+		}
+	}
+	return down, total
+}
+
+// probe makes a single request to the target and reports whether it is up
+func probe(target common.TargetType, simulateDown bool) common.CheckResultType {
+	result := common.CheckResultType{
+		At:       time.Now(),
+		Target:   target.Name,
+		Location: location,
+	}
+
 	client := &http.Client{
-		Timeout: time.Duration(20 * time.Second),
+		Timeout: target.TimeoutDuration(),
 	}
 
-	req, err := http.NewRequest("GET", config.URL, nil)
+	req, err := http.NewRequest("GET", target.URL, nil)
 	if err != nil {
-		log.Fatalln(err)
+		log.Println(err)
+		result.Error = err.Error()
+		return result
 	}
 
 	req.Close = true
 	req.Header.Set("User-Agent", "SupPinger")
 
 	isError := false
-	statusCode := 0
 
 	resp, err := client.Do(req)
+	result.LatencyMs = int64(time.Since(result.At) / time.Millisecond)
 	if err != nil && err != io.EOF {
 		fmt.Printf("err: %+v\n", err)
 		fmt.Printf("resp: %+v\n", resp)
 		isError = true
+		result.Error = err.Error()
 	}
 	if resp != nil {
-		defer resp.Body.Close()
-		statusCode = resp.StatusCode
+		resp.Body.Close()
+		result.StatusCode = resp.StatusCode
+		if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+			expiry := resp.TLS.PeerCertificates[0].NotAfter
+			result.CertExpiry = &expiry
+		}
 	}
 
-	if simulateDown || isError || statusCode != http.StatusOK {
-		log.Println("Site is down. Status is ", statusCode)
-		status.NumErrors++
-		if status.NumErrors >= 5 && !status.Disabled {
-			callDevTeam()
-		}
-	} else {
-		status.NumErrors = 0
+	if simulateDown {
+		result.Error = "simulated down"
 	}
+	result.Up = !simulateDown && !isError && result.StatusCode == http.StatusOK
+	return result
+}
 
-	status.LastStatus = statusCode
-	status.LastRunAt = time.Now()
-	common.SetStatus(status)
+func localTargets() []common.TargetType {
+	return common.GetConfig().AllTargets()
 }
 
 func main() {
@@ -135,7 +350,7 @@ func main() {
 		cli.StringFlag{
 			Name:   "web_auth",
 			Value:  "",
-			Usage:  "basic http auth for web server. format: username:password",
+			Usage:  "admin login for the web server, created on startup if that user doesn't exist. format: username:password",
 			EnvVar: "WEB_AUTH",
 		},
 
@@ -143,12 +358,122 @@ func main() {
 			Name:  "forever",
 			Usage: "run ping on repeat",
 		},
+		cli.IntFlag{
+			Name:   "workers",
+			Usage:  "max number of checks to run at the same time",
+			EnvVar: "WORKERS",
+			Value:  4,
+		},
 		cli.BoolFlag{
 			Name:  "web",
 			Usage: "run web server",
 		},
+
+		cli.StringFlag{
+			Name:   "location",
+			Value:  common.DefaultLocation,
+			Usage:  "name of the location checks are made from",
+			EnvVar: "SUP_LOCATION",
+		},
+		cli.BoolFlag{
+			Name:  "probe",
+			Usage: "run as a remote probe agent, reporting results to --central_url",
+		},
+		cli.StringFlag{
+			Name:   "central_url",
+			Value:  "",
+			Usage:  "url of the central sup web server that probe agents report to",
+			EnvVar: "CENTRAL_URL",
+		},
+		cli.StringFlag{
+			Name:   "probe_token",
+			Value:  "",
+			Usage:  "shared secret probe agents use to talk to the central web server. probe api is off if empty",
+			EnvVar: "PROBE_TOKEN",
+		},
+
+		cli.StringFlag{
+			Name:   "config",
+			Usage:  "YAML, JSON or TOML file to load the config from, with ${NAME} replaced by environment variables. reloaded on SIGHUP or when the file changes",
+			EnvVar: "SUP_CONFIG",
+		},
+		cli.BoolFlag{
+			Name:   "config_authoritative",
+			Usage:  "only allow config changes through the --config file. the web editor becomes read-only. give every sup process the same setting",
+			EnvVar: "SUP_CONFIG_AUTHORITATIVE",
+		},
+
+		cli.StringFlag{
+			Name:   "secret_key",
+			Usage:  "key secrets in the config are encrypted with in redis, from 'sup secrets genkey'. to rotate, put the new key first and old ones after it, comma separated",
+			EnvVar: "SUP_SECRET_KEY",
+		},
+		cli.StringFlag{
+			Name:   "secret_key_file",
+			Usage:  "file with secret keys, one per line, current key first. used after any --secret_key keys",
+			EnvVar: "SUP_SECRET_KEY_FILE",
+		},
+
+		cli.StringFlag{
+			Name:   "oidc_issuer",
+			Usage:  "OpenID Connect issuer url for single sign-on to the web server. sso is off if empty",
+			EnvVar: "OIDC_ISSUER",
+		},
+		cli.StringFlag{
+			Name:   "oidc_client_id",
+			Usage:  "client id registered with the OpenID Connect provider",
+			EnvVar: "OIDC_CLIENT_ID",
+		},
+		cli.StringFlag{
+			Name:   "oidc_client_secret",
+			Usage:  "client secret registered with the OpenID Connect provider. leave empty for public clients",
+			EnvVar: "OIDC_CLIENT_SECRET",
+		},
+		cli.StringFlag{
+			Name:   "oidc_redirect_url",
+			Usage:  "url the provider sends people back to, e.g. https://sup.example.com/login/oidc/callback",
+			EnvVar: "OIDC_REDIRECT_URL",
+		},
+		cli.StringFlag{
+			Name:   "oidc_role_claim",
+			Value:  "groups",
+			Usage:  "ID token claim that roles are mapped from",
+			EnvVar: "OIDC_ROLE_CLAIM",
+		},
+		cli.StringFlag{
+			Name:   "oidc_admin_groups",
+			Usage:  "comma separated role claim values that make someone an admin",
+			EnvVar: "OIDC_ADMIN_GROUPS",
+		},
+		cli.StringFlag{
+			Name:   "oidc_responder_groups",
+			Usage:  "comma separated role claim values that make someone a responder",
+			EnvVar: "OIDC_RESPONDER_GROUPS",
+		},
+		cli.StringFlag{
+			Name:   "oidc_viewer_groups",
+			Usage:  "comma separated role claim values that make someone a viewer",
+			EnvVar: "OIDC_VIEWER_GROUPS",
+		},
+		cli.StringFlag{
+			Name:   "oidc_default_role",
+			Usage:  "role for people who match none of the groups. they can't sign in if empty",
+			EnvVar: "OIDC_DEFAULT_ROLE",
+		},
 	}
 
+	app.Before = func(c *cli.Context) error {
+		common.RedisURL = c.GlobalString("redis_url")
+		keys, err := secretKeys(c)
+		if err == nil {
+			err = common.SetSecretKeys(keys)
+		}
+		exitOnError(err)
+		return nil
+	}
+
+	app.Commands = []cli.Command{tokensCommand, usersCommand, secretsCommand}
+
 	app.Action = func(c *cli.Context) {
 		if c.GlobalString("logfile") != "" {
 			logfile, err := os.OpenFile(c.GlobalString("logfile"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
@@ -157,36 +482,72 @@ func main() {
 			log.SetOutput(logfile)
 		}
 
-		common.RedisURL = c.GlobalString("redis_url")
-
-		config := common.GetConfig()
+		location = c.GlobalString("location")
+		simulateDown := c.GlobalBool("down")
 
-		if c.GlobalBool("down") {
+		if simulateDown {
 			log.Println("We're going to pretend the site is down, even if it's not")
 		}
 
+		// probe agents get their targets from the central server instead
+		if path := c.GlobalString("config"); path != "" && !c.GlobalBool("probe") {
+			common.SetConfigFile(path, c.GlobalBool("config_authoritative"))
+			if err := loadConfigFile(); err != nil {
+				log.Fatal(err)
+			}
+			go watchConfigFile()
+		}
+
 		if c.GlobalBool("web") {
-			err := webserver.StartWebServer(":"+strconv.Itoa(c.GlobalInt("port")), c.GlobalString("web_auth"))
+			oidcConfig := webserver.OIDCConfig{
+				Issuer:          c.GlobalString("oidc_issuer"),
+				ClientID:        c.GlobalString("oidc_client_id"),
+				ClientSecret:    c.GlobalString("oidc_client_secret"),
+				RedirectURL:     c.GlobalString("oidc_redirect_url"),
+				RoleClaim:       c.GlobalString("oidc_role_claim"),
+				AdminGroups:     splitList(c.GlobalString("oidc_admin_groups")),
+				ResponderGroups: splitList(c.GlobalString("oidc_responder_groups")),
+				ViewerGroups:    splitList(c.GlobalString("oidc_viewer_groups")),
+				DefaultRole:     c.GlobalString("oidc_default_role"),
+			}
+			err := webserver.StartWebServer(":"+strconv.Itoa(c.GlobalInt("port")), c.GlobalString("web_auth"), c.GlobalString("probe_token"), oidcConfig)
 			if err != nil {
 				log.Fatal(err)
 			}
+		} else if c.GlobalBool("probe") {
+			agent := newProbeAgent(c.GlobalString("central_url"), c.GlobalString("probe_token"), simulateDown)
+			s := newScheduler(c.GlobalInt("workers"), nil, agent.Targets, agent.Check)
+			s.Run()
 		} else if c.GlobalBool("forever") {
-			fmt.Printf("Pinging %s every %d seconds\n", config.URL, c.GlobalInt("pingFreq"))
-			for {
-				pingSite(c)
-				pingFreq := config.PingFreq
-				if pingFreq < 60 {
-					log.Println("pingFreq is to low, bumping it up to 60")
-					pingFreq = 60
+			lease := newLeaderLease()
+			go lease.Run()
+			s := newScheduler(c.GlobalInt("workers"), lease, localTargets, func(t common.TargetType) {
+				pingSite(t, simulateDown)
+			})
+			s.Run()
+		} else {
+			for _, target := range localTargets() {
+				if !target.IsActive(time.Now()) {
+					fmt.Printf("Skipping %s, paused (schedule)\n", target.URL)
+					continue
 				}
-				time.Sleep(time.Duration(pingFreq) * time.Second)
+				fmt.Printf("Pinging %s\n", target.URL)
+				pingSite(target, simulateDown)
 			}
-		} else {
-			fmt.Printf("Pinging %s\n", config.URL)
-			pingSite(c)
 		}
 	}
 
 	app.Run(os.Args)
 
 }
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/topscore/sup/common"
+)
+
+// tokensCommand manages API tokens from the command line
+var tokensCommand = cli.Command{
+	Name:  "tokens",
+	Usage: "manage API tokens",
+	Subcommands: []cli.Command{
+		{
+			Name:  "create",
+			Usage: "create a token called NAME and print it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "scope",
+					Value: common.ScopeRead,
+					Usage: "one of " + strings.Join(common.Scopes, ", "),
+				},
+				cli.DurationFlag{
+					Name:  "expires",
+					Usage: "how long the token is valid for, e.g. 720h. never expires if not set",
+				},
+			},
+			Action: func(c *cli.Context) {
+				var expiresAt time.Time
+				if d := c.Duration("expires"); d > 0 {
+					expiresAt = time.Now().Add(d)
+				}
+				t, token, err := common.CreateToken(c.Args().First(), c.String("scope"), expiresAt)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				auditCLI("token.create", t.Name+" ("+t.Scope+")")
+				fmt.Println(token)
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "list tokens",
+			Action: func(c *cli.Context) {
+				w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tNAME\tSCOPE\tEXPIRES\tLAST USED")
+				for _, t := range common.GetTokens() {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, t.Scope, formatTokenTime(t.ExpiresAt), formatTokenTime(t.LastUsedAt))
+				}
+				w.Flush()
+			},
+		},
+		{
+			Name:  "revoke",
+			Usage: "revoke the token with the given ID",
+			Action: func(c *cli.Context) {
+				if !common.RevokeToken(c.Args().First()) {
+					fmt.Fprintln(os.Stderr, "no such token")
+					os.Exit(1)
+				}
+				auditCLI("token.revoke", c.Args().First())
+			},
+		},
+	},
+}
+
+func formatTokenTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02 15:04 MST")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/codegangsta/cli"
+	"github.com/topscore/sup/common"
+)
+
+// usersCommand manages web users from the command line
+var usersCommand = cli.Command{
+	Name:  "users",
+	Usage: "manage web users",
+	Subcommands: []cli.Command{
+		{
+			Name:  "add",
+			Usage: "add a user called NAME. the password is read from stdin",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "role",
+					Value: common.RoleViewer,
+					Usage: "one of " + strings.Join(common.Roles, ", "),
+				},
+			},
+			Action: func(c *cli.Context) {
+				u, err := common.CreateUser(c.Args().First(), readPassword(), c.String("role"))
+				exitOnError(err)
+				auditCLI("user.create", u.Username+" ("+u.Role+")")
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "list users",
+			Action: func(c *cli.Context) {
+				w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+				fmt.Fprintln(w, "USERNAME\tROLE\tCREATED")
+				for _, u := range common.GetUsers() {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", u.Username, u.Role, u.CreatedAt.Format("2006-01-02 15:04 MST"))
+				}
+				w.Flush()
+			},
+		},
+		{
+			Name:  "role",
+			Usage: "change the role of user NAME to ROLE",
+			Action: func(c *cli.Context) {
+				exitOnError(common.SetUserRole(c.Args().Get(0), c.Args().Get(1)))
+				auditCLI("user.role", c.Args().Get(0)+" is now "+c.Args().Get(1))
+			},
+		},
+		{
+			Name:  "passwd",
+			Usage: "set the password of user NAME and sign them out everywhere. the password is read from stdin",
+			Action: func(c *cli.Context) {
+				exitOnError(common.SetUserPassword(c.Args().First(), readPassword()))
+				auditCLI("user.password", c.Args().First())
+			},
+		},
+		{
+			Name:  "remove",
+			Usage: "remove user NAME",
+			Action: func(c *cli.Context) {
+				if !common.DeleteUser(c.Args().First()) {
+					exitOnError(fmt.Errorf("no such user"))
+				}
+				auditCLI("user.delete", c.Args().First())
+			},
+		},
+	},
+}
+
+// readPassword reads the first line of stdin, so passwords don't end up in
+// shell history
+func readPassword() string {
+	fmt.Fprint(os.Stderr, "password: ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
+}
+
+// auditCLI records a change made from the command line in the audit log
+func auditCLI(action, detail string) {
+	actor := "cli"
+	if u, err := user.Current(); err == nil {
+		actor = "cli:" + u.Username
+	}
+	common.Audit(common.AuditEventType{Actor: actor, Action: action, Detail: detail})
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,466 @@
+package webserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+const apiPrefix = "/api/v1"
+
+// apiError is returned by the config update funcs to pick the status code
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e apiError) Error() string {
+	return e.message
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeAPIError writes err with its status if it is an apiError, and as a bad
+// request otherwise
+func writeAPIError(w http.ResponseWriter, err error) {
+	var e apiError
+	if errors.As(err, &e) {
+		writeError(w, e.status, e.message)
+		return
+	}
+	if errors.Is(err, common.ErrConfigReadOnly) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// readJSON decodes the request body into v, rejecting unknown fields
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// apiRecover turns panics into JSON 500s instead of dropping the connection
+func apiRecover(c *web.C, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if e := recover(); e != nil {
+				log.Printf("api: %s %s: %v\n", r.Method, r.URL.Path, e)
+				writeError(w, http.StatusInternalServerError, "internal error")
+			}
+		}()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// targets
+
+// findTarget returns the index in config.Targets of the target with the given
+// name. The legacy URL setting is moved into Targets first, so it can be
+// edited like any other target.
+func findTarget(config *common.ConfigType, name string) int {
+	if name == common.DefaultTargetName && config.URL != "" {
+		config.Targets = append([]common.TargetType{{Name: common.DefaultTargetName, URL: config.URL}}, config.Targets...)
+		config.URL = ""
+	}
+	for i, t := range config.Targets {
+		if t.Name == name || (t.Name == "" && t.URL == name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func targetExists(config common.ConfigType, name string) bool {
+	for _, t := range config.AllTargets() {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// targetReferrers lists what refers to the target by name: targets that have
+// it as a parent, and status page components that show it
+func targetReferrers(config common.ConfigType, name string) []string {
+	refs := []string{}
+	for _, t := range config.Targets {
+		if containsString(t.Parents, name) {
+			refs = append(refs, "target "+t.Name)
+		}
+	}
+	for _, component := range config.StatusPage.Components {
+		if containsString(component.Targets, name) {
+			refs = append(refs, "status page component "+component.Name)
+		}
+	}
+	return refs
+}
+
+func validateTarget(config common.ConfigType, t common.TargetType) error {
+	if t.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	for _, p := range t.Parents {
+		if p == t.Name {
+			return fmt.Errorf("a target can't be its own parent")
+		}
+		if !targetExists(config, p) {
+			return fmt.Errorf("unknown parent %q", p)
+		}
+	}
+	return nil
+}
+
+func apiTargetsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, common.GetConfig().AllTargets())
+}
+
+func apiTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name := c.URLParams["name"]
+	for _, t := range common.GetConfig().AllTargets() {
+		if t.Name == name {
+			writeJSON(w, http.StatusOK, t)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "no such target")
+}
+
+func apiCreateTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var t common.TargetType
+	if !readJSON(w, r, &t) {
+		return
+	}
+
+	err := updateConfig(c, r, "target.create", t.Name, func(config *common.ConfigType) error {
+		if targetExists(*config, t.Name) {
+			return apiError{http.StatusConflict, "a target with that name already exists"}
+		}
+		if err := validateTarget(*config, t); err != nil {
+			return err
+		}
+		config.Targets = append(config.Targets, t)
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func apiUpdateTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var t common.TargetType
+	if !readJSON(w, r, &t) {
+		return
+	}
+	name := c.URLParams["name"]
+	if t.Name == "" {
+		t.Name = name
+	}
+
+	err := updateConfig(c, r, "target.update", name, func(config *common.ConfigType) error {
+		i := findTarget(config, name)
+		if i < 0 {
+			return apiError{http.StatusNotFound, "no such target"}
+		}
+		if t.Name != name {
+			return apiError{http.StatusBadRequest, errTargetRename.Error()}
+		}
+		if err := validateTarget(*config, t); err != nil {
+			return err
+		}
+		config.Targets[i] = t
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func apiDeleteTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name := c.URLParams["name"]
+	err := updateConfig(c, r, "target.delete", name, func(config *common.ConfigType) error {
+		i := findTarget(config, name)
+		if i < 0 {
+			return apiError{http.StatusNotFound, "no such target"}
+		}
+		if refs := targetReferrers(*config, name); len(refs) > 0 {
+			return apiError{http.StatusConflict, "still used by " + strings.Join(refs, ", ")}
+		}
+		config.Targets = append(config.Targets[:i], config.Targets[i+1:]...)
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiTargetStatusRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name := c.URLParams["name"]
+	if !targetExists(common.GetConfig(), name) {
+		writeError(w, http.StatusNotFound, "no such target")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		common.TargetStatusType
+		Locations map[string]common.CheckResultType
+	}{common.GetTargetStatus(name), common.GetLocationResults(name)})
+}
+
+func apiTargetHistoryRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name := c.URLParams["name"]
+	if !targetExists(common.GetConfig(), name) {
+		writeError(w, http.StatusNotFound, "no such target")
+		return
+	}
+
+	q := r.URL.Query()
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	skip, _ := strconv.Atoi(q.Get("skip"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+	writeJSON(w, http.StatusOK, common.GetHistory(name, before, max(skip, 0), limit))
+}
+
+// contacts
+
+type contactType struct {
+	Phone string
+}
+
+func apiContactsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	contacts := []contactType{}
+	for _, phone := range common.GetConfig().Phones {
+		contacts = append(contacts, contactType{phone})
+	}
+	writeJSON(w, http.StatusOK, contacts)
+}
+
+func apiCreateContactRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var contact contactType
+	if !readJSON(w, r, &contact) {
+		return
+	}
+	contact.Phone = strings.TrimSpace(contact.Phone)
+	if contact.Phone == "" {
+		writeError(w, http.StatusBadRequest, "Phone is required")
+		return
+	}
+
+	err := updateConfig(c, r, "contact.create", contact.Phone, func(config *common.ConfigType) error {
+		for _, p := range config.Phones {
+			if p == contact.Phone {
+				return apiError{http.StatusConflict, "that phone number is already a contact"}
+			}
+		}
+		config.Phones = append(config.Phones, contact.Phone)
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, contact)
+}
+
+func apiDeleteContactRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	phone := c.URLParams["phone"]
+	err := updateConfig(c, r, "contact.delete", phone, func(config *common.ConfigType) error {
+		for i, p := range config.Phones {
+			if p == phone {
+				config.Phones = append(config.Phones[:i], config.Phones[i+1:]...)
+				return nil
+			}
+		}
+		return apiError{http.StatusNotFound, "no such contact"}
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// maintenance windows
+
+func apiMaintenanceListRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, common.GetMaintenanceWindows())
+}
+
+func apiMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	m, ok := common.GetMaintenanceWindow(c.URLParams["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such maintenance window")
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+func apiCreateMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var m common.MaintenanceWindowType
+	if !readJSON(w, r, &m) {
+		return
+	}
+	if err := m.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	m.ID = ""
+	m = common.AddMaintenanceWindow(m)
+	audit(c, r, "maintenance.create", maintenanceDetail(m))
+	writeJSON(w, http.StatusCreated, m)
+}
+
+func apiUpdateMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	id := c.URLParams["id"]
+	if _, ok := common.GetMaintenanceWindow(id); !ok {
+		writeError(w, http.StatusNotFound, "no such maintenance window")
+		return
+	}
+
+	var m common.MaintenanceWindowType
+	if !readJSON(w, r, &m) {
+		return
+	}
+	if err := m.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	m.ID = id
+	m = common.AddMaintenanceWindow(m)
+	audit(c, r, "maintenance.update", maintenanceDetail(m))
+	writeJSON(w, http.StatusOK, m)
+}
+
+func apiDeleteMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	m, ok := common.GetMaintenanceWindow(c.URLParams["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such maintenance window")
+		return
+	}
+	common.DeleteMaintenanceWindow(m.ID)
+	audit(c, r, "maintenance.delete", maintenanceDetail(m))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// status and alerting
+
+type alertingType struct {
+	Enabled bool
+}
+
+func apiStatusRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	targets := map[string]common.TargetStatusType{}
+	for _, t := range common.GetConfig().AllTargets() {
+		targets[t.Name] = common.GetTargetStatus(t.Name)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Enabled bool
+		Leader  string
+		Targets map[string]common.TargetStatusType
+	}{!common.GetStatus().Disabled, common.GetLeader(), targets})
+}
+
+func apiAlertingRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, alertingType{!common.GetStatus().Disabled})
+}
+
+func apiSetAlertingRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var alerting alertingType
+	if !readJSON(w, r, &alerting) {
+		return
+	}
+
+	status := common.GetStatus()
+	status.Disabled = !alerting.Enabled
+	log.Printf("setting disabled to %t\n", status.Disabled)
+	common.SetStatus(status)
+	auditAlerting(c, r, alerting.Enabled)
+	writeJSON(w, http.StatusOK, alerting)
+}
+
+// apiNotFoundRoute also handles known paths called with the wrong method,
+// which the router marks by setting the valid methods
+func apiNotFoundRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	if methods, ok := c.Env[web.ValidMethodsKey].([]string); ok {
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeError(w, http.StatusNotFound, "no such endpoint")
+}
+
+func openAPIRoute(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, openAPISpec)
+}
+
+func apiMux(auth func(*web.C, http.Handler) http.Handler) *web.Mux {
+	m := web.New()
+	m.Use(apiRecover)
+	m.Use(auth)
+
+	m.Get(apiPrefix+"/status", apiStatusRoute)
+	m.Get(apiPrefix+"/alerting", apiAlertingRoute)
+	m.Put(apiPrefix+"/alerting", apiSetAlertingRoute)
+
+	m.Get(apiPrefix+"/targets", apiTargetsRoute)
+	m.Post(apiPrefix+"/targets", apiCreateTargetRoute)
+	m.Get(apiPrefix+"/targets/:name", apiTargetRoute)
+	m.Put(apiPrefix+"/targets/:name", apiUpdateTargetRoute)
+	m.Delete(apiPrefix+"/targets/:name", apiDeleteTargetRoute)
+	m.Get(apiPrefix+"/targets/:name/status", apiTargetStatusRoute)
+	m.Get(apiPrefix+"/targets/:name/history", apiTargetHistoryRoute)
+
+	m.Get(apiPrefix+"/contacts", apiContactsRoute)
+	m.Post(apiPrefix+"/contacts", apiCreateContactRoute)
+	m.Delete(apiPrefix+"/contacts/:phone", apiDeleteContactRoute)
+
+	m.Get(apiPrefix+"/maintenance", apiMaintenanceListRoute)
+	m.Post(apiPrefix+"/maintenance", apiCreateMaintenanceRoute)
+	m.Get(apiPrefix+"/maintenance/:id", apiMaintenanceRoute)
+	m.Put(apiPrefix+"/maintenance/:id", apiUpdateMaintenanceRoute)
+	m.Delete(apiPrefix+"/maintenance/:id", apiDeleteMaintenanceRoute)
+	m.Get(apiPrefix+"/audit", apiAuditRoute)
+
+	m.NotFound(apiNotFoundRoute)
+	return m
+}
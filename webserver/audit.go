@@ -0,0 +1,131 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+const defaultAuditLimit = 50
+const maxAuditLimit = 500
+
+// audit records a change made by whoever is making the request
+func audit(c web.C, r *http.Request, action, detail string) {
+	auditDiff(c, r, action, detail, "")
+}
+
+func auditDiff(c web.C, r *http.Request, action, detail, diff string) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	common.Audit(common.AuditEventType{
+		Actor:        actor(c),
+		IP:           ip,
+		ForwardedFor: r.Header.Get("X-Forwarded-For"),
+		Action:       action,
+		Detail:       detail,
+		Diff:         diff,
+	})
+}
+
+func auditAlerting(c web.C, r *http.Request, enabled bool) {
+	if enabled {
+		audit(c, r, "alerting.enable", "")
+	} else {
+		audit(c, r, "alerting.disable", "")
+	}
+}
+
+// updateConfig is common.UpdateConfig, plus an audit entry showing what changed
+func updateConfig(c web.C, r *http.Request, action, detail string, fn func(*common.ConfigType) error) error {
+	var before, after common.ConfigType
+	err := common.UpdateConfig(actor(c), func(config *common.ConfigType) error {
+		// a deep copy of what this attempt started from, since fn can change
+		// targets in place
+		data, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		before = common.ConfigType{}
+		if err := json.Unmarshal(data, &before); err != nil {
+			return err
+		}
+		if err := fn(config); err != nil {
+			return err
+		}
+		after = *config
+		return nil
+	})
+	if err == nil {
+		auditDiff(c, r, action, detail, common.ConfigDiff(before, after))
+	}
+	return err
+}
+
+// auditQuery reads the filter and paging parameters shared by the page and the API
+func auditQuery(r *http.Request) (common.AuditFilterType, int64, int, error) {
+	q := r.URL.Query()
+	filter := common.AuditFilterType{Actor: q.Get("actor"), Action: q.Get("action")}
+
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, 0, 0, fmt.Errorf("%s must be an RFC 3339 time, like 2006-01-02T15:04:05Z", p.name)
+			}
+			*p.t = t
+		}
+	}
+
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	return filter, before, limit, nil
+}
+
+func auditRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	filter, before, limit, err := auditQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page := common.GetAuditLog(filter, before, limit)
+
+	q := r.URL.Query()
+	q.Set("before", strconv.FormatInt(page.Next, 10))
+	templateArgs := map[string]interface{}{
+		"events":       page.Events,
+		"next":         page.Next,
+		"olderURL":     "/audit?" + q.Encode(),
+		"filterActor":  filter.Actor,
+		"filterAction": filter.Action,
+		"since":        q.Get("since"),
+		"until":        q.Get("until"),
+	}
+	render(c, w, "audit", templateArgs)
+}
+
+func apiAuditRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	filter, before, limit, err := auditQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, common.GetAuditLog(filter, before, limit))
+}
@@ -0,0 +1,227 @@
+package webserver
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// keys in c.Env set by webAuth
+const (
+	actorKey = "sup.actor" // who made the request, as shown in incident timelines
+	scopeKey = "sup.scope" // what they are allowed to do
+	csrfKey  = "sup.csrf"  // the CSRF token forms must send back
+)
+
+const sessionCookie = "sup_session"
+
+// CSRF tokens are double-submit: a random cookie that forms send back as a
+// field or header. Other sites can make a browser send the cookie but can't
+// read it.
+const (
+	csrfCookie = "sup_csrf"
+	csrfField  = "csrf_token"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// requests that change something but only need the silence scope
+var silencePaths = map[string]bool{
+	"/maintenance":             true,
+	"/maintenance/delete":      true,
+	"/incident/ack":            true,
+	"/incident/note":           true,
+	apiPrefix + "/alerting":    true,
+	apiPrefix + "/maintenance": true,
+}
+
+// pages only admins can see, because they manage access or show who did what
+var adminPaths = []string{"/users", "/tokens", "/audit", apiPrefix + "/audit"}
+
+// requiredScope is the token scope a request needs
+func requiredScope(r *http.Request) string {
+	path := r.URL.Path
+	if strings.HasPrefix(path, apiPrefix+"/maintenance/") {
+		path = apiPrefix + "/maintenance"
+	}
+	for _, p := range adminPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return common.ScopeAdmin
+		}
+	}
+
+	switch {
+	case path == "/setEnabled": // a GET, but it turns calls on and off
+		return common.ScopeSilence
+	case r.Method == "GET" || r.Method == "HEAD" || path == "/logout":
+		return common.ScopeRead
+	case silencePaths[path]:
+		return common.ScopeSilence
+	}
+	return common.ScopeAdmin
+}
+
+func secureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// ensureCSRFCookie returns the request's CSRF token, setting a new one if it
+// doesn't have one yet
+func ensureCSRFCookie(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(csrfCookie); err == nil && len(cookie.Value) == 64 {
+		return cookie.Value
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	token := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   secureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token
+}
+
+// csrfOK checks the CSRF token on requests a browser could be tricked into
+// sending from another site: form posts, and the /setEnabled link. JSON
+// bodies and other methods need a CORS preflight, so they are let through.
+func csrfOK(r *http.Request, token string) bool {
+	if r.URL.Path != "/setEnabled" {
+		if r.Method != "POST" || strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			return true
+		}
+	}
+
+	given := r.Header.Get(csrfHeader)
+	if given == "" {
+		given = r.FormValue(csrfField)
+	}
+	return given != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// webAuth lets a request through if it carries a bearer token, a session
+// cookie or basic auth credentials whose scope or role covers the request.
+// Before any users exist, everyone is let through as before, unless single
+// sign-on is set up. Errors are
+// written as JSON if jsonErrors is set; otherwise people who aren't signed in
+// are sent to the login page.
+//
+// Browsers resend basic auth credentials on their own, so form posts with
+// basic auth need a CSRF token like session ones. Scripts should use a bearer
+// token, which is never checked for CSRF.
+func webAuth(jsonErrors bool) func(*web.C, http.Handler) http.Handler {
+	fail := func(w http.ResponseWriter, status int, message string) {
+		if jsonErrors {
+			writeError(w, status, message)
+		} else {
+			http.Error(w, message, status)
+		}
+	}
+
+	return func(c *web.C, h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.Env == nil {
+				c.Env = map[interface{}]interface{}{}
+			}
+			csrf := ensureCSRFCookie(w, r)
+			c.Env[csrfKey] = csrf
+
+			actor, scope, checkCSRF := "", "", true
+			header := r.Header.Get("Authorization")
+			if strings.HasPrefix(header, "Bearer ") {
+				token, ok := common.AuthenticateToken(strings.TrimPrefix(header, "Bearer "))
+				if !ok {
+					fail(w, http.StatusUnauthorized, "invalid or expired token")
+					return
+				}
+				actor, scope, checkCSRF = "token "+token.Name, token.Scope, false
+			} else if user, pass, ok := r.BasicAuth(); ok {
+				u, ok := common.AuthenticateUser(user, pass)
+				if !ok {
+					w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+					fail(w, http.StatusUnauthorized, "Unauthorized")
+					return
+				}
+				actor, scope = u.Username, common.RoleScope(u.Role)
+			} else if u, ok := sessionUser(r); ok {
+				actor, scope = u.Username, common.RoleScope(u.Role)
+			} else if oidc == nil && !common.HasUsers() {
+				actor, scope = "anonymous", common.ScopeAdmin
+			} else if jsonErrors || r.Method != "GET" {
+				fail(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			} else {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+				return
+			}
+
+			if !common.ScopeAllows(scope, requiredScope(r)) {
+				fail(w, http.StatusForbidden, fmt.Sprintf("%s can't do this", actor))
+				return
+			}
+			if checkCSRF && !csrfOK(r, csrf) {
+				fail(w, http.StatusForbidden, "missing or wrong CSRF token. reload the page and try again")
+				return
+			}
+
+			c.Env[actorKey] = actor
+			c.Env[scopeKey] = scope
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sessionUser returns the user signed in with the request's session cookie
+func sessionUser(r *http.Request) (common.UserType, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return common.UserType{}, false
+	}
+	session, ok := common.GetSession(cookie.Value)
+	if !ok {
+		return common.UserType{}, false
+	}
+	return common.GetUser(session.Username)
+}
+
+func setSessionCookie(w http.ResponseWriter, r *http.Request, session common.SessionType) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    session.ID,
+		Path:     "/",
+		MaxAge:   int(common.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   secureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// actor is who is making the request, for incident timelines
+func actor(c web.C) string {
+	if a, ok := c.Env[actorKey].(string); ok && a != "" {
+		return a
+	}
+	return "anonymous"
+}
+
+// render fills in the template with the signed in user and CSRF token, which
+// every page with a form needs
+func render(c web.C, w http.ResponseWriter, name string, templateArgs map[string]interface{}) {
+	templateArgs["csrf"] = c.Env[csrfKey]
+	templateArgs["actor"] = actor(c)
+	templateArgs["scope"] = c.Env[scopeKey]
+	fmt.Fprintln(w, getTemplate(name, templateArgs))
+}
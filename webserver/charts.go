@@ -0,0 +1,110 @@
+package webserver
+
+import (
+	"bytes"
+	"fmt"
+	baseTemplate "html/template"
+	"time"
+
+	"github.com/topscore/sup/common"
+)
+
+var chartRanges = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+const (
+	chartWidth   = 600
+	chartHeight  = 60  // height of the latency plot
+	stripHeight  = 10  // height of the availability strip under it
+	chartBuckets = 120 // results are averaged into this many slots
+)
+
+type chartBucket struct {
+	latencySum int64
+	checks     int
+	good       int
+}
+
+// renderChart draws response time and availability for the central worker's
+// results between from and to as an inline SVG. Periods with failed checks are
+// shaded. They aren't incidents: a check that failed once is shaded too.
+func renderChart(results []common.CheckResultType, from, to time.Time) baseTemplate.HTML {
+	buckets := make([]chartBucket, chartBuckets)
+	slot := to.Sub(from) / chartBuckets
+	maxLatency := int64(1)
+
+	for _, r := range results {
+		if r.State == "" || r.At.Before(from) || !r.At.Before(to) {
+			continue
+		}
+		i := int(r.At.Sub(from) / slot)
+		if i >= chartBuckets {
+			i = chartBuckets - 1
+		}
+		b := &buckets[i]
+		b.checks++
+		b.latencySum += r.LatencyMs
+		if r.State == common.StateUp {
+			b.good++
+		}
+	}
+	for _, b := range buckets {
+		if b.checks > 0 && b.latencySum/int64(b.checks) > maxLatency {
+			maxLatency = b.latencySum / int64(b.checks)
+		}
+	}
+
+	barWidth := float64(chartWidth) / chartBuckets
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-size="10" font-family="sans-serif">`, chartWidth, chartHeight+stripHeight+14)
+
+	// periods with failed checks
+	for i := 0; i < chartBuckets; i++ {
+		if buckets[i].checks == 0 || buckets[i].good == buckets[i].checks {
+			continue
+		}
+		start := i
+		for i+1 < chartBuckets && buckets[i+1].checks > buckets[i+1].good {
+			i++
+		}
+		fmt.Fprintf(&svg, `<rect x="%.1f" y="0" width="%.1f" height="%d" fill="#FF4136" fill-opacity="0.15"><title>failed checks from %s to %s</title></rect>`,
+			float64(start)*barWidth, float64(i-start+1)*barWidth, chartHeight,
+			from.Add(slot*time.Duration(start)).Format("Jan 2 15:04"), from.Add(slot*time.Duration(i+1)).Format("Jan 2 15:04"))
+	}
+
+	// response time
+	fmt.Fprint(&svg, `<polyline fill="none" stroke="#0074D9" stroke-width="1.5" points="`)
+	for i, b := range buckets {
+		if b.checks == 0 {
+			continue
+		}
+		y := float64(chartHeight) - float64(b.latencySum/int64(b.checks))*float64(chartHeight-4)/float64(maxLatency)
+		fmt.Fprintf(&svg, "%.1f,%.1f ", (float64(i)+0.5)*barWidth, y)
+	}
+	fmt.Fprint(&svg, `"/>`)
+	fmt.Fprintf(&svg, `<text x="2" y="10" fill="#555">%dms</text>`, maxLatency)
+
+	// availability strip
+	for i, b := range buckets {
+		color := "#DDDDDD"
+		title := "no data"
+		if b.checks > 0 {
+			color = "#3D9970"
+			if b.good < b.checks {
+				color = "#FF4136"
+			}
+			title = fmt.Sprintf("%.1f%% up", 100*float64(b.good)/float64(b.checks))
+		}
+		fmt.Fprintf(&svg, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"><title>%s</title></rect>`,
+			float64(i)*barWidth, chartHeight+2, barWidth, stripHeight, color, title)
+	}
+
+	fmt.Fprintf(&svg, `<text x="0" y="%d" fill="#555">%s</text>`, chartHeight+stripHeight+13, from.Format("Jan 2 15:04"))
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="#555" text-anchor="end">%s</text>`, chartWidth, chartHeight+stripHeight+13, to.Format("Jan 2 15:04"))
+	fmt.Fprint(&svg, `</svg>`)
+
+	return baseTemplate.HTML(svg.String())
+}
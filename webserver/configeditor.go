@@ -0,0 +1,578 @@
+package webserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// The structured config editor. Every form is saved through saveConfig, which
+// refuses changes that would add validation errors. Errors are shown next to
+// the field they are about, with what was typed kept in the form.
+
+// formErrors maps form field names to what is wrong with them
+type formErrors map[string]string
+
+// saveConfig applies fn and saves the config if that doesn't make it invalid.
+// Problems the config already had don't block saving, so one bad field
+// somewhere doesn't lock everyone out of the forms.
+func saveConfig(c web.C, r *http.Request, action, detail string, fn func(*common.ConfigType) error) error {
+	return updateConfig(c, r, action, detail, func(config *common.ConfigType) error {
+		before := map[string]int{}
+		if errs, ok := config.Validate().(common.ConfigErrors); ok {
+			for _, e := range errs {
+				before[errorKey(config, e)]++
+			}
+		}
+
+		if err := fn(config); err != nil {
+			return err
+		}
+
+		var added common.ConfigErrors
+		if errs, ok := config.Validate().(common.ConfigErrors); ok {
+			for _, e := range errs {
+				key := errorKey(config, e)
+				if before[key] > 0 {
+					before[key]--
+					continue
+				}
+				added = append(added, e)
+			}
+		}
+		if len(added) > 0 {
+			return added
+		}
+		return nil
+	})
+}
+
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// errorKey is how saveConfig tells whether an error was already there. Paths
+// hold list indexes, which shift when an earlier item is deleted, so targets
+// are named instead and other indexes are dropped. saveConfig counts keys, so
+// a second error like one that was already there still counts as new.
+func errorKey(config *common.ConfigType, e common.FieldError) string {
+	path := e.Path
+	var i int
+	if n, _ := fmt.Sscanf(path, "Targets[%d]", &i); n == 1 && i >= 0 && i < len(config.Targets) {
+		path = "Targets[" + strconv.Quote(config.Targets[i].Name) + "]" + strings.TrimPrefix(path, fmt.Sprintf("Targets[%d]", i))
+	}
+	return listIndex.ReplaceAllString(path, "[]") + ": " + e.Message
+}
+
+// fieldErrors sorts validation errors into the form fields under prefix, like
+// Targets[2], and everything else. With no prefix, the form fields are the
+// top level settings.
+func fieldErrors(err error, prefix string) (formErrors, []string) {
+	fields := formErrors{}
+	other := []string{}
+
+	errs, ok := err.(common.ConfigErrors)
+	if !ok {
+		return fields, []string{err.Error()}
+	}
+	for _, e := range errs {
+		field := ""
+		switch {
+		case prefix == "" && e.Path != "" && !strings.ContainsAny(e.Path, ".["):
+			field = e.Path
+		case prefix != "" && strings.HasPrefix(e.Path, prefix+"."):
+			field = strings.TrimPrefix(e.Path, prefix+".")
+			// errors about one parent belong to the whole list
+			if !strings.HasPrefix(field, "SLOs[") {
+				field = strings.SplitN(field, "[", 2)[0]
+			}
+		}
+		if field == "" {
+			other = append(other, e.Error())
+			continue
+		}
+		fields[field] = e.Message
+	}
+	return fields, other
+}
+
+func formInt(r *http.Request, name string, errs formErrors) int {
+	s := strings.TrimSpace(r.FormValue(name))
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		errs[name] = "must be a whole number"
+	}
+	return n
+}
+
+// configSource tells the config pages where the config comes from. If it is
+// managed in a file, they are shown read-only.
+func configSource(templateArgs map[string]interface{}) {
+	path, authoritative := common.ConfigFile()
+	templateArgs["configFile"] = path
+	templateArgs["readOnly"] = authoritative
+}
+
+// the overview: targets, contacts and settings
+
+type settingsForm struct {
+	PingFreq         string
+	HistoryDays      string
+	ConfigVersions   string
+	TwilioSID        string
+	TwilioAuthToken  string
+	TwilioCallFrom   string
+	HipchatAuthToken string
+	HipchatRoom      string
+}
+
+// settingsFromConfig fills in the settings form. Secrets are masked.
+func settingsFromConfig(config common.ConfigType) settingsForm {
+	config = config.Masked()
+	number := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	return settingsForm{
+		PingFreq:         number(config.PingFreq),
+		HistoryDays:      number(config.HistoryDays),
+		ConfigVersions:   number(config.ConfigVersions),
+		TwilioSID:        config.TwilioSID,
+		TwilioAuthToken:  config.TwilioAuthToken,
+		TwilioCallFrom:   config.TwilioCallFrom,
+		HipchatAuthToken: config.HipchatAuthToken,
+		HipchatRoom:      config.HipchatRoom,
+	}
+}
+
+// contactRow is one line of the contacts form
+type contactRow struct {
+	Phone string
+	Error string
+}
+
+func contactRows(phones []string) []contactRow {
+	rows := []contactRow{}
+	for _, p := range phones {
+		rows = append(rows, contactRow{Phone: p})
+	}
+	// always a blank row to add a number in
+	return append(rows, contactRow{})
+}
+
+type editorState struct {
+	contacts       []contactRow
+	settings       settingsForm
+	settingsErrors formErrors
+	errors         []string
+	success        string
+}
+
+func renderConfigEditor(c web.C, w http.ResponseWriter, state editorState) {
+	config := common.GetConfig()
+	if state.contacts == nil {
+		state.contacts = contactRows(config.Phones)
+	}
+	if state.settingsErrors == nil {
+		state.settings = settingsFromConfig(config)
+		state.settingsErrors = formErrors{}
+	}
+
+	targets := []map[string]interface{}{}
+	for _, t := range config.AllTargets() {
+		every := fmt.Sprintf("%ds", t.Interval)
+		if t.Schedule != "" {
+			every = t.Schedule
+		}
+		targets = append(targets, map[string]interface{}{
+			"name":  t.Name,
+			"url":   t.URL,
+			"every": every,
+			"tags":  strings.Join(t.Tags, ", "),
+		})
+	}
+
+	templateArgs := map[string]interface{}{
+		"targets":        targets,
+		"contacts":       state.contacts,
+		"settings":       state.settings,
+		"settingsErrors": state.settingsErrors,
+		"errors":         state.errors,
+		"success":        state.success,
+		"minInterval":    common.MinInterval,
+		"maxInterval":    common.MaxInterval,
+	}
+	configSource(templateArgs)
+	render(c, w, "configeditor", templateArgs)
+}
+
+func configEditorRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	renderConfigEditor(c, w, editorState{success: r.URL.Query().Get("success")})
+}
+
+// saveContactsRoute saves the phone numbers. The "add" button shows another
+// blank row without saving.
+func saveContactsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	rows := []contactRow{}
+	phones := []string{}
+	valid := true
+	for i, phone := range r.Form["phone"] {
+		phone = strings.TrimSpace(phone)
+		if phone == "" || r.FormValue(fmt.Sprintf("remove%d", i)) != "" {
+			continue
+		}
+		row := contactRow{Phone: phone}
+		if !common.ValidPhone(phone) {
+			row.Error = "isn't an E.164 phone number like +15551234567"
+			valid = false
+		}
+		rows = append(rows, row)
+		phones = append(phones, phone)
+	}
+
+	if r.FormValue("add") != "" || !valid {
+		if !valid {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		renderConfigEditor(c, w, editorState{contacts: append(rows, contactRow{})})
+		return
+	}
+
+	err := saveConfig(c, r, "contacts.update", strings.Join(phones, ", "), func(config *common.ConfigType) error {
+		config.Phones = phones
+		return nil
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, other := fieldErrors(err, "")
+		renderConfigEditor(c, w, editorState{contacts: append(rows, contactRow{}), errors: other})
+		return
+	}
+	http.Redirect(w, r, "/config?success=Contacts+saved", http.StatusFound)
+}
+
+func saveSettingsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	form := settingsForm{
+		PingFreq:         strings.TrimSpace(r.FormValue("PingFreq")),
+		HistoryDays:      strings.TrimSpace(r.FormValue("HistoryDays")),
+		ConfigVersions:   strings.TrimSpace(r.FormValue("ConfigVersions")),
+		TwilioSID:        strings.TrimSpace(r.FormValue("TwilioSID")),
+		TwilioAuthToken:  strings.TrimSpace(r.FormValue("TwilioAuthToken")),
+		TwilioCallFrom:   strings.TrimSpace(r.FormValue("TwilioCallFrom")),
+		HipchatAuthToken: strings.TrimSpace(r.FormValue("HipchatAuthToken")),
+		HipchatRoom:      strings.TrimSpace(r.FormValue("HipchatRoom")),
+	}
+	errs := formErrors{}
+	pingFreq := formInt(r, "PingFreq", errs)
+	historyDays := formInt(r, "HistoryDays", errs)
+	configVersions := formInt(r, "ConfigVersions", errs)
+
+	var other []string
+	if len(errs) == 0 {
+		err := saveConfig(c, r, "settings.update", "", func(config *common.ConfigType) error {
+			current := *config
+			config.PingFreq = pingFreq
+			config.HistoryDays = historyDays
+			config.ConfigVersions = configVersions
+			config.TwilioSID = form.TwilioSID
+			config.TwilioAuthToken = form.TwilioAuthToken
+			config.TwilioCallFrom = form.TwilioCallFrom
+			config.HipchatAuthToken = form.HipchatAuthToken
+			config.HipchatRoom = form.HipchatRoom
+			config.KeepSecrets(current)
+			return nil
+		})
+		if err == nil {
+			http.Redirect(w, r, "/config?success=Settings+saved", http.StatusFound)
+			return
+		}
+		errs, other = fieldErrors(err, "")
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	renderConfigEditor(c, w, editorState{settings: form, settingsErrors: errs, errors: other})
+}
+
+// targets
+
+// targetForm is the target form as typed, so it can be shown again with errors
+type targetForm struct {
+	Original    string // the target's name before this edit. empty for new targets
+	Name        string
+	URL         string
+	Interval    string
+	Timeout     string
+	Retries     string
+	RetryDelay  string
+	Quorum      string
+	Tags        string
+	Parents     []string
+	Schedule    string
+	ActiveHours string
+	ActiveDays  string
+	Timezone    string
+	SLOs        []sloRow
+}
+
+type sloRow struct {
+	Name       string
+	Objective  string
+	LatencyMs  string
+	WindowDays string
+}
+
+func targetFormFrom(t common.TargetType, original string) targetForm {
+	number := func(n int64) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	form := targetForm{
+		Original:    original,
+		Name:        t.Name,
+		URL:         t.URL,
+		Interval:    number(int64(t.Interval)),
+		Timeout:     number(int64(t.Timeout)),
+		Retries:     number(int64(t.Retries)),
+		RetryDelay:  number(int64(t.RetryDelay)),
+		Quorum:      number(int64(t.Quorum)),
+		Tags:        strings.Join(t.Tags, ", "),
+		Parents:     t.Parents,
+		Schedule:    t.Schedule,
+		ActiveHours: t.ActiveHours,
+		ActiveDays:  t.ActiveDays,
+		Timezone:    t.Timezone,
+	}
+	for _, slo := range t.SLOs {
+		form.SLOs = append(form.SLOs, sloRow{
+			Name:       slo.Name,
+			Objective:  strconv.FormatFloat(slo.Objective, 'f', -1, 64),
+			LatencyMs:  number(slo.LatencyMs),
+			WindowDays: number(int64(slo.WindowDays)),
+		})
+	}
+	form.SLOs = append(form.SLOs, sloRow{})
+	return form
+}
+
+// readTargetForm parses the form over the existing target, so fields the form
+// doesn't show are kept
+func readTargetForm(r *http.Request, t common.TargetType) (targetForm, common.TargetType, formErrors) {
+	errs := formErrors{}
+	form := targetForm{
+		Original:    r.FormValue("original"),
+		Name:        strings.TrimSpace(r.FormValue("Name")),
+		URL:         strings.TrimSpace(r.FormValue("URL")),
+		Interval:    r.FormValue("Interval"),
+		Timeout:     r.FormValue("Timeout"),
+		Retries:     r.FormValue("Retries"),
+		RetryDelay:  r.FormValue("RetryDelay"),
+		Quorum:      r.FormValue("Quorum"),
+		Tags:        r.FormValue("Tags"),
+		Parents:     r.Form["Parents"],
+		Schedule:    strings.TrimSpace(r.FormValue("Schedule")),
+		ActiveHours: strings.TrimSpace(r.FormValue("ActiveHours")),
+		ActiveDays:  strings.TrimSpace(r.FormValue("ActiveDays")),
+		Timezone:    strings.TrimSpace(r.FormValue("Timezone")),
+	}
+
+	t.Name = form.Name
+	t.URL = form.URL
+	t.Interval = formInt(r, "Interval", errs)
+	t.Timeout = formInt(r, "Timeout", errs)
+	t.Retries = formInt(r, "Retries", errs)
+	t.RetryDelay = formInt(r, "RetryDelay", errs)
+	t.Quorum = formInt(r, "Quorum", errs)
+	t.Tags = splitList(form.Tags)
+	t.Parents = form.Parents
+	t.Schedule = form.Schedule
+	t.ActiveHours = form.ActiveHours
+	t.ActiveDays = form.ActiveDays
+	t.Timezone = form.Timezone
+	if t.Name == "" {
+		errs["Name"] = "is required"
+	}
+
+	t.SLOs = nil
+	names, objectives, latencies, windows := r.Form["SLOName"], r.Form["SLOObjective"], r.Form["SLOLatencyMs"], r.Form["SLOWindowDays"]
+	for i := range names {
+		row := sloRow{
+			Name:       strings.TrimSpace(names[i]),
+			Objective:  strings.TrimSpace(valueAt(objectives, i)),
+			LatencyMs:  strings.TrimSpace(valueAt(latencies, i)),
+			WindowDays: strings.TrimSpace(valueAt(windows, i)),
+		}
+		if r.FormValue(fmt.Sprintf("SLORemove%d", i)) != "" || row == (sloRow{}) {
+			continue
+		}
+
+		n := len(t.SLOs)
+		slo := common.SLOType{Name: row.Name}
+		var err error
+		if slo.Objective, err = strconv.ParseFloat(row.Objective, 64); err != nil {
+			errs[fmt.Sprintf("SLOs[%d].Objective", n)] = "must be a number like 99.9"
+		}
+		if row.LatencyMs != "" {
+			if slo.LatencyMs, err = strconv.ParseInt(row.LatencyMs, 10, 64); err != nil {
+				errs[fmt.Sprintf("SLOs[%d].LatencyMs", n)] = "must be a whole number"
+			}
+		}
+		if row.WindowDays != "" {
+			if slo.WindowDays, err = strconv.Atoi(row.WindowDays); err != nil {
+				errs[fmt.Sprintf("SLOs[%d].WindowDays", n)] = "must be a whole number"
+			}
+		}
+		form.SLOs = append(form.SLOs, row)
+		t.SLOs = append(t.SLOs, slo)
+	}
+	form.SLOs = append(form.SLOs, sloRow{})
+	return form, t, errs
+}
+
+func valueAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+func renderTargetForm(c web.C, w http.ResponseWriter, form targetForm, errs formErrors, other []string) {
+	parents := []map[string]interface{}{}
+	for _, t := range common.GetConfig().AllTargets() {
+		if t.Name == form.Original {
+			continue
+		}
+		parents = append(parents, map[string]interface{}{
+			"name":    t.Name,
+			"checked": containsString(form.Parents, t.Name),
+		})
+	}
+
+	templateArgs := map[string]interface{}{
+		"form":        form,
+		"errors":      errs,
+		"other":       other,
+		"parents":     parents,
+		"minInterval": common.MinInterval,
+		"maxInterval": common.MaxInterval,
+		"maxTimeout":  common.MaxTimeout,
+		"maxRetries":  common.MaxRetries,
+	}
+	configSource(templateArgs)
+	render(c, w, "target", templateArgs)
+}
+
+// targetFormRoute shows the form for the target called name, or a blank one
+// for a new target
+func targetFormRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		renderTargetForm(c, w, targetFormFrom(common.TargetType{}, ""), formErrors{}, nil)
+		return
+	}
+
+	config := common.GetConfig()
+	i := findTarget(&config, name)
+	if i < 0 {
+		http.Error(w, "no such target", http.StatusNotFound)
+		return
+	}
+	renderTargetForm(c, w, targetFormFrom(config.Targets[i], name), formErrors{}, nil)
+}
+
+func saveTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	original := r.FormValue("original")
+
+	var existing common.TargetType
+	if original != "" {
+		config := common.GetConfig()
+		i := findTarget(&config, original)
+		if i < 0 {
+			http.Error(w, "no such target", http.StatusNotFound)
+			return
+		}
+		existing = config.Targets[i]
+	}
+
+	form, t, errs := readTargetForm(r, existing)
+	if r.FormValue("add") != "" {
+		renderTargetForm(c, w, form, formErrors{}, nil)
+		return
+	}
+	if len(errs) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		renderTargetForm(c, w, form, errs, nil)
+		return
+	}
+
+	action := "target.update"
+	if original == "" {
+		action = "target.create"
+	}
+	var index int
+	err := saveConfig(c, r, action, t.Name, func(config *common.ConfigType) error {
+		if original == "" {
+			index = len(config.Targets)
+			config.Targets = append(config.Targets, t)
+			return nil
+		}
+
+		index = findTarget(config, original)
+		if index < 0 {
+			return common.ConfigErrors{{Message: "the target was deleted while you were editing it"}}
+		}
+		if t.Name != original {
+			return common.ConfigErrors{{Path: fmt.Sprintf("Targets[%d].Name", index), Message: errTargetRename.Error()}}
+		}
+		config.Targets[index] = t
+		return nil
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fields, other := fieldErrors(err, fmt.Sprintf("Targets[%d]", index))
+		renderTargetForm(c, w, form, fields, other)
+		return
+	}
+	http.Redirect(w, r, "/config?success="+url.QueryEscape(t.Name+" saved"), http.StatusFound)
+}
+
+// targets keep their name, since their status, history, incidents and
+// maintenance windows are all kept under it
+var errTargetRename = errors.New("targets can't be renamed. add a new target and delete this one instead")
+
+func deleteTargetRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	name := r.FormValue("name")
+	err := saveConfig(c, r, "target.delete", name, func(config *common.ConfigType) error {
+		i := findTarget(config, name)
+		if i < 0 {
+			return common.ConfigErrors{{Message: "no such target"}}
+		}
+		config.Targets = append(config.Targets[:i], config.Targets[i+1:]...)
+		return nil
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, other := fieldErrors(err, "")
+		renderConfigEditor(c, w, editorState{errors: append([]string{"Couldn't delete " + name + ":"}, other...)})
+		return
+	}
+	http.Redirect(w, r, "/config?success="+url.QueryEscape(name+" deleted"), http.StatusFound)
+}
@@ -0,0 +1,66 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// configVersionsRoute lists the kept versions and shows the diff between the
+// two picked with from and to. By default that is the latest change.
+func configVersionsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	versions := common.GetConfigVersions()
+
+	var latest int64
+	if len(versions) > 0 {
+		latest = versions[0].Version
+	}
+
+	q := r.URL.Query()
+	from, _ := strconv.ParseInt(q.Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(q.Get("to"), 10, 64)
+	if to == 0 {
+		to = latest
+	}
+	if from == 0 && len(versions) > 1 {
+		from = versions[1].Version
+	}
+
+	templateArgs := map[string]interface{}{
+		"versions": versions,
+		"latest":   latest,
+		"from":     from,
+		"to":       to,
+		"error":    q.Get("error"),
+		"success":  q.Get("success"),
+	}
+	fromVersion, okFrom := common.GetConfigVersion(from)
+	toVersion, okTo := common.GetConfigVersion(to)
+	if okFrom && okTo {
+		diff := common.ConfigDiff(fromVersion.Config, toVersion.Config)
+		if diff == "" {
+			diff = "no differences"
+		}
+		templateArgs["diff"] = diff
+	}
+	configSource(templateArgs)
+	render(c, w, "configversions", templateArgs)
+}
+
+func rollbackConfigRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	version, _ := strconv.ParseInt(r.FormValue("version"), 10, 64)
+
+	diff, err := common.RollbackConfig(version, actor(c))
+	if err != nil {
+		http.Redirect(w, r, "/config/versions?error="+url.QueryEscape(err.Error()), http.StatusFound)
+		return
+	}
+	auditDiff(c, r, "config.rollback", fmt.Sprintf("to version %d", version), diff)
+	http.Redirect(w, r, fmt.Sprintf("/config/versions?success=Rolled+back+to+version+%d", version), http.StatusFound)
+}
@@ -0,0 +1,62 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+const defaultHistoryLimit = 50
+const maxHistoryLimit = 1000
+
+// historyPage reads the target, before, skip and limit query params and loads that page
+func historyPage(r *http.Request) (string, common.HistoryPageType, bool) {
+	q := r.URL.Query()
+	target := q.Get("target")
+	if target == "" {
+		return "", common.HistoryPageType{}, false
+	}
+
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	skip, _ := strconv.Atoi(q.Get("skip"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	return target, common.GetHistory(target, before, max(skip, 0), limit), true
+}
+
+func historyJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	_, page, ok := historyPage(r)
+	if !ok {
+		http.Error(w, "target is required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func historyRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	target, page, ok := historyPage(r)
+	if !ok {
+		http.Error(w, "target is required", http.StatusBadRequest)
+		return
+	}
+
+	templateArgs := map[string]interface{}{
+		"target":  target,
+		"results": page.Results,
+		"next":    page.Next,
+		"skip":    page.Skip,
+	}
+	render(c, w, "history", templateArgs)
+}
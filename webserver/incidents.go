@@ -0,0 +1,98 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+const defaultIncidentsLimit = 25
+const maxIncidentsLimit = 500
+
+// incidentsPage reads the before and limit query params and loads that page
+func incidentsPage(r *http.Request) common.IncidentPageType {
+	q := r.URL.Query()
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = defaultIncidentsLimit
+	}
+	if limit > maxIncidentsLimit {
+		limit = maxIncidentsLimit
+	}
+	return common.GetIncidents(before, limit)
+}
+
+func incidentsJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(incidentsPage(r))
+}
+
+func incidentsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	page := incidentsPage(r)
+	templateArgs := map[string]interface{}{
+		"incidents": page.Incidents,
+		"next":      page.Next,
+	}
+	render(c, w, "incidents", templateArgs)
+}
+
+func incidentJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	incident, ok := common.GetIncident(r.URL.Query().Get("id"))
+	if !ok {
+		http.Error(w, "no such incident", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(incident)
+}
+
+func incidentRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	incident, ok := common.GetIncident(r.URL.Query().Get("id"))
+	if !ok {
+		http.Error(w, "no such incident", http.StatusNotFound)
+		return
+	}
+
+	templateArgs := map[string]interface{}{
+		"incident": incident,
+		"duration": incident.Duration(),
+	}
+	render(c, w, "incident", templateArgs)
+}
+
+func ackIncidentRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id := r.FormValue("id")
+	if !common.AcknowledgeIncident(id, actor(c)) {
+		http.Error(w, "no such incident", http.StatusNotFound)
+		return
+	}
+	audit(c, r, "incident.ack", id)
+	http.Redirect(w, r, "/incident?id="+url.QueryEscape(id), http.StatusFound)
+}
+
+func incidentNoteRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id := r.FormValue("id")
+	text := strings.TrimSpace(r.FormValue("text"))
+	if text == "" {
+		http.Error(w, "note is empty", http.StatusBadRequest)
+		return
+	}
+
+	event := common.IncidentEventType{Kind: common.EventNote, By: actor(c), Text: text}
+	if !common.AddIncidentEvent(id, event) {
+		http.Error(w, "no such incident", http.StatusNotFound)
+		return
+	}
+	audit(c, r, "incident.note", id)
+	http.Redirect(w, r, "/incident?id="+url.QueryEscape(id), http.StatusFound)
+}
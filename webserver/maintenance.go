@@ -0,0 +1,143 @@
+package webserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// format used by datetime-local inputs
+const formTimeFormat = "2006-01-02T15:04"
+
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// maintenanceFromForm builds a window from the home page form. The form has
+// three kinds: "adhoc" starts now and lasts Minutes, "scheduled" runs from Start
+// to End, and "recurring" lasts Minutes every time Schedule fires.
+func maintenanceFromForm(r *http.Request) (common.MaintenanceWindowType, string) {
+	m := common.MaintenanceWindowType{
+		Reason:   strings.TrimSpace(r.FormValue("reason")),
+		Targets:  splitList(r.FormValue("targets")),
+		Tags:     splitList(r.FormValue("tags")),
+		Timezone: strings.TrimSpace(r.FormValue("timezone")),
+	}
+	minutes, _ := strconv.Atoi(r.FormValue("minutes"))
+	loc := time.Local
+	if m.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(m.Timezone); err != nil {
+			return m, "Unknown timezone"
+		}
+	}
+
+	switch r.FormValue("kind") {
+	case "adhoc":
+		if minutes <= 0 {
+			return m, "Duration must be a positive number of minutes"
+		}
+		m.Start = time.Now()
+		m.End = m.Start.Add(time.Duration(minutes) * time.Minute)
+
+	case "scheduled":
+		var err1, err2 error
+		m.Start, err1 = time.ParseInLocation(formTimeFormat, r.FormValue("start"), loc)
+		m.End, err2 = time.ParseInLocation(formTimeFormat, r.FormValue("end"), loc)
+		if err1 != nil || err2 != nil || !m.End.After(m.Start) {
+			return m, "Scheduled windows need a start and a later end"
+		}
+
+	case "recurring":
+		m.Schedule = strings.TrimSpace(r.FormValue("schedule"))
+		m.Duration = minutes
+		if _, err := common.ParseCron(m.Schedule); err != nil {
+			return m, err.Error()
+		}
+		if minutes <= 0 {
+			return m, "Duration must be a positive number of minutes"
+		}
+
+	default:
+		return m, "Unknown maintenance kind"
+	}
+
+	return m, ""
+}
+
+func addMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	m, errMsg := maintenanceFromForm(r)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	m = common.AddMaintenanceWindow(m)
+	audit(c, r, "maintenance.create", maintenanceDetail(m))
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func deleteMaintenanceRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	if m, ok := common.GetMaintenanceWindow(r.FormValue("id")); ok {
+		common.DeleteMaintenanceWindow(m.ID)
+		audit(c, r, "maintenance.delete", maintenanceDetail(m))
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// maintenanceDetail describes a window for the audit log
+func maintenanceDetail(m common.MaintenanceWindowType) string {
+	if m.Reason == "" {
+		return m.ID
+	}
+	return m.ID + ": " + m.Reason
+}
+
+// maintenanceArgs describes the windows for the home page template
+func maintenanceArgs(now time.Time) []map[string]interface{} {
+	windows := []map[string]interface{}{}
+	for _, m := range common.GetMaintenanceWindows() {
+		when := m.Start.Format("2006-01-02 15:04 MST") + " to " + m.End.Format("2006-01-02 15:04 MST")
+		if m.IsRecurring() {
+			when = m.Schedule + " for " + strconv.Itoa(m.Duration) + " minutes"
+			if m.Timezone != "" {
+				when += " (" + m.Timezone + ")"
+			}
+		}
+
+		scope := strings.Join(append(append([]string{}, m.Targets...), prefixAll("#", m.Tags)...), ", ")
+		if scope == "" {
+			scope = "everything"
+		}
+
+		windows = append(windows, map[string]interface{}{
+			"id":     m.ID,
+			"reason": m.Reason,
+			"scope":  scope,
+			"when":   when,
+			"active": m.IsActive(now),
+		})
+	}
+	return windows
+}
+
+func prefixAll(prefix string, list []string) []string {
+	out := []string{}
+	for _, s := range list {
+		out = append(out, prefix+s)
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package webserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// metricsRoute serves target state and notification counters in the
+// Prometheus text exposition format
+func metricsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	targets := common.GetConfig().AllTargets()
+	statuses := map[string]common.TargetStatusType{}
+	for _, t := range targets {
+		statuses[t.Name] = common.GetTargetStatus(t.Name)
+	}
+
+	metricHeader(w, "sup_target_up", "gauge", "Whether the target is up (1) or down or unreachable (0).")
+	for _, t := range targets {
+		up := 0
+		if statuses[t.Name].State == common.StateUp {
+			up = 1
+		}
+		fmt.Fprintf(w, "sup_target_up{target=%s} %d\n", labelValue(t.Name), up)
+	}
+
+	metricHeader(w, "sup_target_consecutive_failures", "gauge", "Failed checks in a row.")
+	for _, t := range targets {
+		fmt.Fprintf(w, "sup_target_consecutive_failures{target=%s} %d\n", labelValue(t.Name), statuses[t.Name].NumErrors)
+	}
+
+	metricHeader(w, "sup_check_latency_seconds", "histogram", "Check response time.")
+	for _, t := range targets {
+		histograms := common.GetLatencyHistograms(t.Name)
+		for _, loc := range sortedKeys(histograms) {
+			h := histograms[loc]
+			labels := fmt.Sprintf("target=%s,location=%s", labelValue(t.Name), labelValue(loc))
+			for i, le := range common.LatencyBuckets {
+				fmt.Fprintf(w, "sup_check_latency_seconds_bucket{%s,le=\"%s\"} %d\n", labels, strconv.FormatFloat(le, 'g', -1, 64), h.Buckets[i])
+			}
+			fmt.Fprintf(w, "sup_check_latency_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.Count)
+			fmt.Fprintf(w, "sup_check_latency_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(h.SumSeconds, 'g', -1, 64))
+			fmt.Fprintf(w, "sup_check_latency_seconds_count{%s} %d\n", labels, h.Count)
+		}
+	}
+
+	metricHeader(w, "sup_cert_expiry_timestamp_seconds", "gauge", "When the target's TLS certificate expires, as a unix timestamp.")
+	for _, t := range targets {
+		results := common.GetLocationResults(t.Name)
+		for _, loc := range sortedKeys(results) {
+			if expiry := results[loc].CertExpiry; expiry != nil {
+				fmt.Fprintf(w, "sup_cert_expiry_timestamp_seconds{target=%s,location=%s} %d\n", labelValue(t.Name), labelValue(loc), expiry.Unix())
+			}
+		}
+	}
+
+	counts := common.GetNotificationCounts()
+	channels := sortedKeys(counts)
+	metricHeader(w, "sup_notifications_sent_total", "counter", "Notifications sent, by channel.")
+	for _, ch := range channels {
+		fmt.Fprintf(w, "sup_notifications_sent_total{channel=%s} %d\n", labelValue(ch), counts[ch].Sent)
+	}
+	metricHeader(w, "sup_notifications_failed_total", "counter", "Notifications that could not be sent, by channel.")
+	for _, ch := range channels {
+		fmt.Fprintf(w, "sup_notifications_failed_total{channel=%s} %d\n", labelValue(ch), counts[ch].Failed)
+	}
+}
+
+func metricHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue quotes a label value for the exposition format
+func labelValue(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+// sortedKeys returns the keys of a map with string keys, sorted
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
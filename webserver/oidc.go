@@ -0,0 +1,466 @@
+package webserver
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// OIDCConfig sets up single sign-on through an OpenID Connect provider. SSO
+// is off if Issuer is empty.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // empty for public clients, which rely on PKCE alone
+	RedirectURL  string // must end in /login/oidc/callback
+
+	// RoleClaim is the ID token claim roles are mapped from, usually "groups".
+	// It can hold a string or a list of strings. Users get the highest role
+	// any of its values map to, or DefaultRole if none do. If DefaultRole is
+	// empty, users who match nothing can't sign in.
+	RoleClaim       string
+	AdminGroups     []string
+	ResponderGroups []string
+	ViewerGroups    []string
+	DefaultRole     string
+}
+
+const oidcStateCookie = "sup_oidc_state"
+
+// how far apart our clock and the provider's can be
+const oidcClockSkew = time.Minute
+
+// the provider's endpoints and keys, fetched from its discovery document
+type oidcProviderType struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	authEndpoint  string
+	tokenEndpoint string
+	jwksURI       string
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// oidc is nil if SSO is off
+var oidc *oidcProviderType
+
+func newOIDCProvider(config OIDCConfig) (*oidcProviderType, error) {
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc needs a client id and redirect url")
+	}
+	if config.DefaultRole != "" && !common.ValidRole(config.DefaultRole) {
+		return nil, fmt.Errorf("oidc default role must be one of %s", strings.Join(common.Roles, ", "))
+	}
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &oidcProviderType{config: config, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (p *oidcProviderType) getJSON(u string, v interface{}) error {
+	resp, err := p.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// discover loads the provider's endpoints the first time they are needed, so
+// sup still starts if the provider is down
+func (p *oidcProviderType) discover() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.authEndpoint != "" {
+		return nil
+	}
+
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := p.getJSON(p.config.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return err
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.config.Issuer {
+		return fmt.Errorf("provider says its issuer is %s, not %s", doc.Issuer, p.config.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return fmt.Errorf("provider discovery document is missing endpoints")
+	}
+	p.authEndpoint, p.tokenEndpoint, p.jwksURI = doc.AuthorizationEndpoint, doc.TokenEndpoint, doc.JWKSURI
+	return nil
+}
+
+// key returns the provider's signing key with the given id. Keys are fetched
+// again when an unknown id shows up, since that is how providers rotate them.
+func (p *oidcProviderType) key(kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.keys[kid]; ok {
+		return k, nil
+	}
+	if time.Since(p.keysFetchedAt) < 10*time.Second {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(p.jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+	p.keysFetchedAt = time.Now()
+
+	p.keys = map[string]crypto.PublicKey{}
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			p.keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case k.Kty == "EC" && k.Crv == "P-256":
+			x, errX := base64.RawURLEncoding.DecodeString(k.X)
+			y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			p.keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+
+	if k, ok := p.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// verifyIDToken checks the ID token's signature, issuer, audience, expiry and
+// nonce, and returns its claims
+func (p *oidcProviderType) verifyIDToken(token, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed id token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed id token signature")
+	}
+	key, err := p.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" || rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) != nil {
+			return nil, fmt.Errorf("bad id token signature")
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" || len(sig) != 64 ||
+			!ecdsa.Verify(k, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			return nil, fmt.Errorf("bad id token signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing key")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("id token is from %s, not %s", iss, p.config.Issuer)
+	}
+	if !containsString(claimValues(claims["aud"]), p.config.ClientID) {
+		return nil, fmt.Errorf("id token is not for this client")
+	}
+	exp, _ := claims["exp"].(float64)
+	if time.Unix(int64(exp), 0).Add(oidcClockSkew).Before(time.Now()) {
+		return nil, fmt.Errorf("id token has expired")
+	}
+	if n, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(n), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("id token nonce doesn't match")
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("malformed id token")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("malformed id token")
+	}
+	return nil
+}
+
+// claimValues turns a claim that is either a string or a list of strings into a list
+func claimValues(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := []string{}
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// role maps the ID token's role claim onto a sup role. It returns "" if the
+// user shouldn't be let in.
+func (p *oidcProviderType) role(claims map[string]interface{}) string {
+	values := claimValues(claims[p.config.RoleClaim])
+	for _, m := range []struct {
+		role   string
+		groups []string
+	}{
+		{common.RoleAdmin, p.config.AdminGroups},
+		{common.RoleResponder, p.config.ResponderGroups},
+		{common.RoleViewer, p.config.ViewerGroups},
+	} {
+		for _, v := range values {
+			if containsString(m.groups, v) {
+				return m.role
+			}
+		}
+	}
+	return p.config.DefaultRole
+}
+
+// displayName picks a readable name for the user from the ID token. It isn't
+// unique or stable, so users are identified by the sub claim instead.
+func displayName(claims map[string]interface{}) string {
+	for _, claim := range []string{"preferred_username", "email", "sub"} {
+		if s, _ := claims[claim].(string); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// exchange swaps the authorization code for tokens and returns the ID token
+func (p *oidcProviderType) exchange(code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest("POST", p.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+	if body.Error != "" {
+		return "", fmt.Errorf("token endpoint: %s %s", body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("token endpoint didn't return an id token")
+	}
+	return body.IDToken, nil
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// oidcLoginRoute sends the browser to the provider to sign in
+func oidcLoginRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	if err := oidc.discover(); err != nil {
+		log.Printf("oidc discovery failed: %s\n", err)
+		renderLogin(w, r, r.URL.Query().Get("next"), "Single sign-on isn't working right now")
+		return
+	}
+
+	state := randomString()
+	loginState := common.LoginStateType{
+		Verifier: randomString(),
+		Nonce:    randomString(),
+		Next:     safeNext(r.URL.Query().Get("next")),
+	}
+	common.SaveLoginState(hashState(state), loginState)
+
+	// the state is also kept in a cookie, so a callback only works in the
+	// browser that started signing in
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/login/oidc",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   secureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	challenge := sha256.Sum256([]byte(loginState.Verifier))
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {oidc.config.ClientID},
+		"redirect_uri":          {oidc.config.RedirectURL},
+		"scope":                 {"openid profile email"},
+		"state":                 {state},
+		"nonce":                 {loginState.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(oidc.authEndpoint, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, oidc.authEndpoint+sep+params.Encode(), http.StatusFound)
+}
+
+// the state is stored hashed, so what's in redis can't be used to finish a login
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// oidcCallbackRoute is where the provider sends the browser back to
+func oidcCallbackRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	fail := func(message string, err error) {
+		log.Printf("oidc login failed: %s\n", err)
+		renderLogin(w, r, "/", message)
+	}
+
+	q := r.URL.Query()
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || q.Get("state") == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(q.Get("state"))) != 1 {
+		fail("Your sign in expired. Please try again.", fmt.Errorf("state doesn't match"))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Value: "", Path: "/login/oidc", MaxAge: -1})
+
+	state, ok := common.TakeLoginState(hashState(q.Get("state")))
+	if !ok {
+		fail("Your sign in expired. Please try again.", fmt.Errorf("unknown state"))
+		return
+	}
+	if e := q.Get("error"); e != "" {
+		fail("The identity provider didn't sign you in", fmt.Errorf("%s %s", e, q.Get("error_description")))
+		return
+	}
+
+	if err := oidc.discover(); err != nil {
+		fail("Single sign-on isn't working right now", err)
+		return
+	}
+	idToken, err := oidc.exchange(q.Get("code"), state.Verifier)
+	if err != nil {
+		fail("Single sign-on isn't working right now", err)
+		return
+	}
+	claims, err := oidc.verifyIDToken(idToken, state.Nonce)
+	if err != nil {
+		fail("Single sign-on isn't working right now", err)
+		return
+	}
+
+	name := displayName(claims)
+	role := oidc.role(claims)
+	if name == "" || role == "" {
+		fail("You don't have access to sup", fmt.Errorf("%q has no role", name))
+		return
+	}
+	subject, _ := claims["sub"].(string)
+	u, err := common.SSOUser(oidc.config.Issuer, subject, name, role)
+	if err != nil {
+		fail(err.Error(), err)
+		return
+	}
+
+	log.Printf("%s signed in through sso as %s\n", u.Username, u.Role)
+	setSessionCookie(w, r, common.CreateSession(u.Username))
+	c.Env = map[interface{}]interface{}{actorKey: u.Username}
+	audit(c, r, "user.login", "sso as "+u.Role)
+	http.Redirect(w, r, state.Next, http.StatusFound)
+}
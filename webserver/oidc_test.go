@@ -0,0 +1,482 @@
+package webserver
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+const testClientID = "sup-test"
+const testRedirectURL = "https://sup.example.com/login/oidc/callback"
+
+// testProvider is a stand-in identity provider serving discovery, JWKS and
+// token endpoints. The test plays the browser and the provider's sign in page.
+type testProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]testGrant
+}
+
+// what the provider remembers about an authorization code
+type testGrant struct {
+	challenge string
+	claims    map[string]interface{}
+}
+
+// newTestProvider starts a provider and makes it the one sup signs in with
+func newTestProvider(t *testing.T, config OIDCConfig) *testProvider {
+	// failed sign ins show the login page
+	if err := loadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{key: key, codes: map[string]testGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	config.Issuer = p.URL
+	config.ClientID = testClientID
+	config.RedirectURL = testRedirectURL
+	provider, err := newOIDCProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := oidc
+	oidc = provider
+	t.Cleanup(func() { oidc = previous })
+	return p
+}
+
+// token swaps a code for an ID token, checking the PKCE verifier like a real
+// provider would
+func (p *testProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	p.mu.Lock()
+	grant, ok := p.codes[r.Form.Get("code")]
+	delete(p.codes, r.Form.Get("code"))
+	p.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || r.Form.Get("grant_type") != "authorization_code" ||
+		r.Form.Get("client_id") != testClientID || r.Form.Get("redirect_uri") != testRedirectURL ||
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"id_token":   signToken(p.key, "k1", grant.claims),
+		"token_type": "Bearer",
+	})
+}
+
+// authorize signs the user in at the provider for the sign in that sent the
+// browser to location, and returns the code. The ID token gets the nonce sup
+// asked for unless claims already has one.
+func (p *testProvider) authorize(t *testing.T, location string, claims map[string]interface{}) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("sign in doesn't use PKCE: %s", location)
+	}
+	if _, ok := claims["nonce"]; !ok {
+		claims["nonce"] = q.Get("nonce")
+	}
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = testGrant{challenge: q.Get("code_challenge"), claims: claims}
+	p.mu.Unlock()
+	return code
+}
+
+// claims are valid ID token claims for the account
+func (p *testProvider) claims(subject, name string, groups ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                p.URL,
+		"aud":                testClientID,
+		"sub":                subject,
+		"preferred_username": name,
+		"groups":             groups,
+		"iat":                time.Now().Unix(),
+		"exp":                time.Now().Add(5 * time.Minute).Unix(),
+	}
+}
+
+func signToken(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	p := newTestProvider(t, OIDCConfig{})
+	if err := oidc.discover(); err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(claims map[string]interface{})
+		key    *rsa.PrivateKey
+		ok     bool
+	}{
+		{name: "valid", ok: true},
+		{name: "audience list", change: func(c map[string]interface{}) { c["aud"] = []string{"other", testClientID} }, ok: true},
+		{name: "bad signature", key: otherKey},
+		{name: "wrong issuer", change: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", change: func(c map[string]interface{}) { c["aud"] = "other" }},
+		{name: "expired", change: func(c map[string]interface{}) { c["exp"] = time.Now().Add(-oidcClockSkew - time.Minute).Unix() }},
+		{name: "no expiry", change: func(c map[string]interface{}) { delete(c, "exp") }},
+		{name: "nonce mismatch", change: func(c map[string]interface{}) { c["nonce"] = "other" }},
+	}
+	for _, test := range tests {
+		claims := p.claims("123", "alice")
+		claims["nonce"] = "n1"
+		if test.change != nil {
+			test.change(claims)
+		}
+		key := p.key
+		if test.key != nil {
+			key = test.key
+		}
+
+		_, err := oidc.verifyIDToken(signToken(key, "k1", claims), "n1")
+		if test.ok && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%s: token was accepted", test.name)
+		}
+	}
+
+	// a token that isn't signed at all
+	claims, _ := json.Marshal(p.claims("123", "alice"))
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"k1"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + "."
+	if _, err := oidc.verifyIDToken(unsigned, ""); err == nil {
+		t.Errorf("unsigned token was accepted")
+	}
+}
+
+func TestOIDCRole(t *testing.T) {
+	config := OIDCConfig{
+		RoleClaim:       "groups",
+		AdminGroups:     []string{"ops"},
+		ResponderGroups: []string{"oncall"},
+		ViewerGroups:    []string{"staff"},
+		DefaultRole:     common.RoleViewer,
+	}
+	tests := []struct {
+		groups      interface{}
+		defaultRole string
+		role        string
+	}{
+		{[]interface{}{"staff", "ops"}, common.RoleViewer, common.RoleAdmin},
+		{"oncall", common.RoleViewer, common.RoleResponder},
+		{[]interface{}{"staff"}, "", common.RoleViewer},
+		{[]interface{}{"eng"}, common.RoleViewer, common.RoleViewer},
+		{[]interface{}{"eng"}, common.RoleResponder, common.RoleResponder},
+		{[]interface{}{"eng"}, "", ""},
+		{nil, "", ""},
+	}
+	for _, test := range tests {
+		config.DefaultRole = test.defaultRole
+		p := &oidcProviderType{config: config}
+		if role := p.role(map[string]interface{}{"groups": test.groups}); role != test.role {
+			t.Errorf("groups %v with default %q: got role %q, want %q", test.groups, test.defaultRole, role, test.role)
+		}
+	}
+}
+
+// requireRedis points sup at the test redis, or skips the test if there
+// isn't one. SUP_TEST_REDIS_URL sets which redis, and defaults to db 15 on
+// localhost.
+func requireRedis(t *testing.T) {
+	redisURL := os.Getenv("SUP_TEST_REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis://localhost:6379/15"
+	}
+	u, err := url.Parse(redisURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.DialTimeout("tcp", u.Host, time.Second)
+	if err != nil {
+		t.Skipf("no redis at %s", u.Host)
+	}
+	conn.Close()
+
+	previous := common.RedisURL
+	common.RedisURL = redisURL
+	t.Cleanup(func() { common.RedisURL = previous })
+}
+
+// deleteSSOUsers removes the users that signed in through the provider
+func deleteSSOUsers(p *testProvider) {
+	for _, u := range common.GetUsers() {
+		if strings.HasPrefix(u.Subject, p.URL+" ") {
+			common.DeleteUser(u.Username)
+		}
+	}
+}
+
+// testUsername is a username no other test run uses
+func testUsername(name string) string {
+	return fmt.Sprintf("%s%d", name, time.Now().UnixNano())
+}
+
+// startLogin sends a browser to the provider, returning the provider URL it
+// was redirected to and the state cookie
+func startLogin(t *testing.T, next string) (string, *http.Cookie) {
+	w := httptest.NewRecorder()
+	oidcLoginRoute(web.C{}, w, httptest.NewRequest("GET", "/login/oidc?next="+url.QueryEscape(next), nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("sign in returned %d: %s", w.Code, w.Body)
+	}
+	for _, c := range w.Result().Cookies() {
+		if c.Name == oidcStateCookie {
+			return w.Header().Get("Location"), c
+		}
+	}
+	t.Fatal("sign in didn't set the state cookie")
+	return "", nil
+}
+
+// callback is the browser coming back from the provider
+func callback(location string, cookie *http.Cookie, code string) *httptest.ResponseRecorder {
+	u, _ := url.Parse(location)
+	q := url.Values{"state": {u.Query().Get("state")}, "code": {code}}
+	r := httptest.NewRequest("GET", "/login/oidc/callback?"+q.Encode(), nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	oidcCallbackRoute(web.C{}, w, r)
+	return w
+}
+
+// signedInAs returns who the callback signed the browser in as
+func signedInAs(t *testing.T, w *httptest.ResponseRecorder) common.UserType {
+	if w.Code != http.StatusFound {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body)
+	}
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookie {
+			session, ok := common.GetSession(c.Value)
+			if !ok {
+				t.Fatal("callback set a session that doesn't exist")
+			}
+			u, ok := common.GetUser(session.Username)
+			if !ok {
+				t.Fatalf("signed in as %s, who doesn't exist", session.Username)
+			}
+			return u
+		}
+	}
+	t.Fatal("callback didn't sign the browser in")
+	return common.UserType{}
+}
+
+func ssoConfig() OIDCConfig {
+	return OIDCConfig{
+		RoleClaim:       "groups",
+		AdminGroups:     []string{"ops"},
+		ResponderGroups: []string{"oncall"},
+		ViewerGroups:    []string{"staff"},
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+	name := testUsername("alice")
+
+	location, cookie := startLogin(t, "/incidents")
+	w := callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name, "ops")))
+	u := signedInAs(t, w)
+	if u.Username != name || u.DisplayName != name || u.Role != common.RoleAdmin || u.PasswordHash != "" {
+		t.Errorf("signed in as %+v", u)
+	}
+	if u.Subject != p.URL+" sub-1" {
+		t.Errorf("user's subject is %q", u.Subject)
+	}
+	if location := w.Header().Get("Location"); location != "/incidents" {
+		t.Errorf("sent to %s after signing in", location)
+	}
+
+	// the role follows the provider, and the user is found by subject even
+	// after their name changes there
+	location, cookie = startLogin(t, "/")
+	u = signedInAs(t, callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name+"-renamed", "staff"))))
+	if u.Username != name || u.DisplayName != name+"-renamed" || u.Role != common.RoleViewer {
+		t.Errorf("signed in again as %+v", u)
+	}
+}
+
+func TestOIDCLoginWithoutRole(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+	name := testUsername("mallory")
+
+	location, cookie := startLogin(t, "/")
+	w := callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name, "eng")))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("callback returned %d", w.Code)
+	}
+	if _, ok := common.GetUser(name); ok {
+		t.Errorf("user without a role was created")
+	}
+}
+
+func TestOIDCLoginCodeFromAnotherLogin(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+
+	// a code issued to one sign in is useless to another, since the other
+	// doesn't have the PKCE verifier it was issued for
+	victimLocation, _ := startLogin(t, "/")
+	code := p.authorize(t, victimLocation, p.claims("sub-1", testUsername("alice"), "ops"))
+	location, cookie := startLogin(t, "/")
+	if w := callback(location, cookie, code); w.Code != http.StatusUnauthorized {
+		t.Errorf("callback with another sign in's code returned %d", w.Code)
+	}
+}
+
+func TestOIDCStateReplay(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+	name := testUsername("alice")
+
+	// without the state cookie, the callback isn't from the browser that
+	// started signing in
+	location, _ := startLogin(t, "/")
+	if w := callback(location, nil, p.authorize(t, location, p.claims("sub-1", name, "ops"))); w.Code != http.StatusUnauthorized {
+		t.Errorf("callback without the state cookie returned %d", w.Code)
+	}
+
+	location, cookie := startLogin(t, "/")
+	code := p.authorize(t, location, p.claims("sub-1", name, "ops"))
+	signedInAs(t, callback(location, cookie, code))
+
+	// the same state only works once, even with a fresh code
+	if w := callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name, "ops"))); w.Code != http.StatusUnauthorized {
+		t.Errorf("replayed callback returned %d", w.Code)
+	}
+}
+
+func TestOIDCLoginNonceMismatch(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+
+	claims := p.claims("sub-1", testUsername("alice"), "ops")
+	claims["nonce"] = "not the one sup asked for"
+	location, cookie := startLogin(t, "/")
+	if w := callback(location, cookie, p.authorize(t, location, claims)); w.Code != http.StatusUnauthorized {
+		t.Errorf("callback with the wrong nonce returned %d", w.Code)
+	}
+}
+
+func TestOIDCLoginDoesntTakeOverLocalUser(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+	name := testUsername("admin")
+	if _, err := common.CreateUser(name, "local password", common.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	defer common.DeleteUser(name)
+
+	location, cookie := startLogin(t, "/")
+	u := signedInAs(t, callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name, "ops"))))
+	if u.Username == name {
+		t.Fatalf("sso signed in as local user %s", name)
+	}
+	if u.DisplayName != name || u.Role != common.RoleAdmin {
+		t.Errorf("signed in as %+v", u)
+	}
+	if local, ok := common.GetUser(name); !ok || local.PasswordHash == "" || local.Subject != "" || local.Role != common.RoleViewer {
+		t.Errorf("local user was changed: %+v", local)
+	}
+}
+
+func TestOIDCLoginSameNameDifferentSubject(t *testing.T) {
+	requireRedis(t)
+	p := newTestProvider(t, ssoConfig())
+	defer deleteSSOUsers(p)
+	name := testUsername("alice")
+
+	location, cookie := startLogin(t, "/")
+	first := signedInAs(t, callback(location, cookie, p.authorize(t, location, p.claims("sub-1", name, "ops"))))
+	location, cookie = startLogin(t, "/")
+	second := signedInAs(t, callback(location, cookie, p.authorize(t, location, p.claims("sub-2", name, "staff"))))
+
+	if first.Username != name || second.Username == name {
+		t.Errorf("accounts with the same name signed in as %s and %s", first.Username, second.Username)
+	}
+	if u, _ := common.GetUser(name); u.Role != common.RoleAdmin {
+		t.Errorf("second account changed the first's role to %s", u.Role)
+	}
+}
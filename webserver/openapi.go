@@ -0,0 +1,348 @@
+package webserver
+
+// openAPISpec documents the /api/v1 endpoints. Keep it in step with apiMux.
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "sup API",
+    "version": "1",
+    "description": "Manage sup targets, contacts and maintenance windows, and read target status and history. Errors are returned as {\"error\": \"message\"}. A token or user without enough scope or role gets a 403."
+  },
+  "servers": [{"url": "/api/v1"}],
+  "security": [{"basicAuth": []}, {"bearerAuth": []}],
+  "paths": {
+    "/status": {
+      "get": {
+        "summary": "Whether calls are enabled, the current leader and every target's status",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}}
+        }
+      }
+    },
+    "/alerting": {
+      "get": {
+        "summary": "Whether calls are enabled",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Alerting"}}}}
+        }
+      },
+      "put": {
+        "summary": "Enable or disable calls",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Alerting"}}}},
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Alerting"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/targets": {
+      "get": {
+        "summary": "List targets, with defaults filled in",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Target"}}}}}
+        }
+      },
+      "post": {
+        "summary": "Add a target",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Target"}}}},
+        "responses": {
+          "201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Target"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "409": {"$ref": "#/components/responses/Conflict"}
+        }
+      }
+    },
+    "/targets/{name}": {
+      "parameters": [{"$ref": "#/components/parameters/TargetName"}],
+      "get": {
+        "summary": "Get a target",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Target"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "put": {
+        "summary": "Replace a target. The legacy URL setting can be edited as the target named default. Targets can't be renamed.",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Target"}}}},
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Target"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "409": {"$ref": "#/components/responses/Conflict"}
+        }
+      },
+      "delete": {
+        "summary": "Delete a target",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "409": {"$ref": "#/components/responses/Conflict"}
+        }
+      }
+    },
+    "/targets/{name}/status": {
+      "parameters": [{"$ref": "#/components/parameters/TargetName"}],
+      "get": {
+        "summary": "A target's status and the latest result from each location",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/TargetStatusWithLocations"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/targets/{name}/history": {
+      "parameters": [
+        {"$ref": "#/components/parameters/TargetName"},
+        {"name": "before", "in": "query", "description": "unix milliseconds. pass the Next of the previous page", "schema": {"type": "integer", "format": "int64"}},
+        {"name": "skip", "in": "query", "description": "pass the Skip of the previous page", "schema": {"type": "integer"}},
+        {"name": "limit", "in": "query", "schema": {"type": "integer", "default": 50, "maximum": 1000}}
+      ],
+      "get": {
+        "summary": "A target's check results, newest first",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HistoryPage"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/contacts": {
+      "get": {
+        "summary": "List the phone numbers that are called",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Contact"}}}}}
+        }
+      },
+      "post": {
+        "summary": "Add a phone number",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Contact"}}}},
+        "responses": {
+          "201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Contact"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "409": {"$ref": "#/components/responses/Conflict"}
+        }
+      }
+    },
+    "/contacts/{phone}": {
+      "parameters": [{"name": "phone", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "delete": {
+        "summary": "Remove a phone number",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "409": {"$ref": "#/components/responses/Conflict"}
+        }
+      }
+    },
+    "/maintenance": {
+      "get": {
+        "summary": "List maintenance windows that haven't expired",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/MaintenanceWindow"}}}}}
+        }
+      },
+      "post": {
+        "summary": "Add a maintenance window",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MaintenanceWindow"}}}},
+        "responses": {
+          "201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MaintenanceWindow"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/maintenance/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "get": {
+        "summary": "Get a maintenance window",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MaintenanceWindow"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "put": {
+        "summary": "Replace a maintenance window",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MaintenanceWindow"}}}},
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MaintenanceWindow"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "delete": {
+        "summary": "End and delete a maintenance window",
+        "responses": {
+          "204": {"description": "Deleted"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/audit": {
+      "parameters": [
+        {"name": "actor", "in": "query", "schema": {"type": "string"}},
+        {"name": "action", "in": "query", "description": "an exact action like target.delete, or a prefix like user", "schema": {"type": "string"}},
+        {"name": "since", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+        {"name": "until", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+        {"name": "before", "in": "query", "description": "pass the Next of the previous page", "schema": {"type": "integer", "format": "int64"}},
+        {"name": "limit", "in": "query", "schema": {"type": "integer", "default": 50, "maximum": 500}}
+      ],
+      "get": {
+        "summary": "The audit log of changes, newest first. Needs admin",
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AuditPage"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "basicAuth": {"type": "http", "scheme": "basic"},
+      "bearerAuth": {"type": "http", "scheme": "bearer", "description": "API token. read tokens can make GET requests, silence tokens can also change alerting and maintenance, admin tokens can do anything"}
+    },
+    "parameters": {
+      "TargetName": {"name": "name", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "BadRequest": {"description": "Invalid request", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "NotFound": {"description": "Not found", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "Conflict": {"description": "Already exists or is still in use, or the config is managed in a file and can't be changed through the API", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      },
+      "Alerting": {
+        "type": "object",
+        "required": ["Enabled"],
+        "properties": {"Enabled": {"type": "boolean", "description": "whether people get called when targets go down"}}
+      },
+      "Contact": {
+        "type": "object",
+        "required": ["Phone"],
+        "properties": {"Phone": {"type": "string"}}
+      },
+      "SLO": {
+        "type": "object",
+        "properties": {
+          "Name": {"type": "string"},
+          "Objective": {"type": "number", "description": "percent of checks that must be good"},
+          "LatencyMs": {"type": "integer", "description": "checks slower than this are bad. 0 only counts availability"},
+          "WindowDays": {"type": "integer"}
+        }
+      },
+      "Target": {
+        "type": "object",
+        "required": ["Name", "URL"],
+        "properties": {
+          "Name": {"type": "string"},
+          "URL": {"type": "string", "description": "http or https URL"},
+          "Interval": {"type": "integer", "description": "seconds between checks. falls back to PingFreq, then 60"},
+          "Timeout": {"type": "integer", "description": "seconds"},
+          "Retries": {"type": "integer"},
+          "RetryDelay": {"type": "integer", "description": "seconds"},
+          "Tags": {"type": "array", "items": {"type": "string"}},
+          "Parents": {"type": "array", "items": {"type": "string"}, "description": "names of targets this one depends on"},
+          "Quorum": {"type": "integer", "description": "how many locations must see the target down"},
+          "SLOs": {"type": "array", "items": {"$ref": "#/components/schemas/SLO"}},
+          "Schedule": {"type": "string", "description": "cron expression. overrides Interval"},
+          "ActiveHours": {"type": "string", "example": "09:00-17:00"},
+          "ActiveDays": {"type": "string", "example": "Mon-Fri"},
+          "Timezone": {"type": "string", "example": "Europe/London"}
+        }
+      },
+      "TargetStatus": {
+        "type": "object",
+        "properties": {
+          "LastStatus": {"type": "integer"},
+          "LastRunAt": {"type": "string", "format": "date-time"},
+          "NumErrors": {"type": "integer"},
+          "State": {"type": "string", "enum": ["up", "down", "unreachable"]},
+          "UnreachableVia": {"type": "string"},
+          "Alerted": {"type": "boolean"},
+          "RecentStates": {"type": "string"},
+          "Flapping": {"type": "boolean"},
+          "BurnAlerts": {"type": "array", "items": {"type": "string"}},
+          "IncidentID": {"type": "string"}
+        }
+      },
+      "TargetStatusWithLocations": {
+        "allOf": [
+          {"$ref": "#/components/schemas/TargetStatus"},
+          {
+            "type": "object",
+            "properties": {
+              "Locations": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/CheckResult"}}
+            }
+          }
+        ]
+      },
+      "Status": {
+        "type": "object",
+        "properties": {
+          "Enabled": {"type": "boolean"},
+          "Leader": {"type": "string"},
+          "Targets": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/TargetStatus"}}
+        }
+      },
+      "CheckResult": {
+        "type": "object",
+        "properties": {
+          "At": {"type": "string", "format": "date-time"},
+          "Target": {"type": "string"},
+          "Location": {"type": "string"},
+          "StatusCode": {"type": "integer"},
+          "LatencyMs": {"type": "integer"},
+          "Error": {"type": "string"},
+          "Up": {"type": "boolean"},
+          "State": {"type": "string"},
+          "Maintenance": {"type": "boolean"},
+          "CertExpiry": {"type": "string", "format": "date-time"}
+        }
+      },
+      "HistoryPage": {
+        "type": "object",
+        "properties": {
+          "Results": {"type": "array", "items": {"$ref": "#/components/schemas/CheckResult"}},
+          "Next": {"type": "integer", "format": "int64", "description": "pass as before to get the next page. 0 on the last page. a filtered search stops after a few thousand entries, so a page can be short, or empty, and still have a Next"},
+          "Skip": {"type": "integer", "description": "pass as skip to get the next page. how many results at Next this and earlier pages returned"}
+        }
+      },
+      "AuditEvent": {
+        "type": "object",
+        "properties": {
+          "Seq": {"type": "integer", "format": "int64"},
+          "At": {"type": "string", "format": "date-time"},
+          "Actor": {"type": "string"},
+          "IP": {"type": "string"},
+          "ForwardedFor": {"type": "string"},
+          "Action": {"type": "string"},
+          "Detail": {"type": "string"},
+          "Diff": {"type": "string", "description": "for config changes, the changed lines of the config as JSON"}
+        }
+      },
+      "AuditPage": {
+        "type": "object",
+        "properties": {
+          "Events": {"type": "array", "items": {"$ref": "#/components/schemas/AuditEvent"}},
+          "Next": {"type": "integer", "format": "int64", "description": "pass as before to get the next page. 0 on the last page. a filtered search stops after a few thousand entries, so a page can be short, or empty, and still have a Next"}
+        }
+      },
+      "MaintenanceWindow": {
+        "type": "object",
+        "description": "either one-off (Start and End) or recurring (Schedule and Duration)",
+        "properties": {
+          "ID": {"type": "string", "readOnly": true},
+          "Reason": {"type": "string"},
+          "Targets": {"type": "array", "items": {"type": "string"}, "description": "no targets or tags covers everything"},
+          "Tags": {"type": "array", "items": {"type": "string"}},
+          "Start": {"type": "string", "format": "date-time"},
+          "End": {"type": "string", "format": "date-time"},
+          "Schedule": {"type": "string", "description": "cron expression"},
+          "Duration": {"type": "integer", "description": "minutes"},
+          "Timezone": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
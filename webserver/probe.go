@@ -0,0 +1,57 @@
+package webserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// probeAuth only lets through requests that carry the shared probe token
+func probeAuth(token string) func(*web.C, http.Handler) http.Handler {
+	return func(c *web.C, h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func probeTargetsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(common.GetConfig().AllTargets())
+}
+
+func probeResultsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	var result common.CheckResultType
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		http.Error(w, "Invalid json", http.StatusBadRequest)
+		return
+	}
+	if result.Target == "" || result.Location == "" || result.Location == common.DefaultLocation {
+		http.Error(w, "Target and Location are required", http.StatusBadRequest)
+		return
+	}
+
+	// only the central worker decides the overall state
+	result.State = ""
+	common.SetLocationResult(result)
+	common.AddCheckResult(result, common.GetConfig().HistoryRetentionFor(result.Target))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func probeMux(token string) *web.Mux {
+	m := web.New()
+	m.Use(probeAuth(token))
+	m.Get("/probe/targets", probeTargetsRoute)
+	m.Post("/probe/results", probeResultsRoute)
+	return m
+}
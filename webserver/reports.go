@@ -0,0 +1,254 @@
+package webserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+var reportRanges = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// reports read weeks of every target's history, so each one is only built
+// this often
+const reportCacheFor = 5 * time.Minute
+
+var reportCache struct {
+	sync.Mutex
+	reports map[string]cachedReportType
+}
+
+type cachedReportType struct {
+	report  interface{}
+	builtAt time.Time
+}
+
+// cachedReport returns what build made for the same key in the last
+// reportCacheFor, or builds it
+func cachedReport(key string, build func() interface{}) interface{} {
+	reportCache.Lock()
+	defer reportCache.Unlock()
+
+	for k, cached := range reportCache.reports {
+		if time.Since(cached.builtAt) > reportCacheFor {
+			delete(reportCache.reports, k)
+		}
+	}
+	if cached, ok := reportCache.reports[key]; ok {
+		return cached.report
+	}
+	if reportCache.reports == nil {
+		reportCache.reports = map[string]cachedReportType{}
+	}
+	report := build()
+	reportCache.reports[key] = cachedReportType{report: report, builtAt: time.Now()}
+	return report
+}
+
+// reportKey tells reports apart in the cache. The fixed ranges move with the
+// clock, so they are keyed by name.
+func reportKey(kind, name string, from, to time.Time, exclude bool) string {
+	if name == "custom" {
+		name = from.Format("2006-01-02") + "/" + to.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s %s %t", kind, name, exclude)
+}
+
+// reportParams reads the range (24h, 7d, 30d or custom with from and to
+// dates) and exclude_maintenance query params
+func reportParams(r *http.Request) (string, time.Time, time.Time, bool, error) {
+	q := r.URL.Query()
+	name := q.Get("range")
+	if name == "" {
+		name = "30d"
+	}
+	exclude := q.Get("exclude_maintenance") == "1"
+	now := time.Now()
+
+	if name == "custom" {
+		from, err := time.ParseInLocation("2006-01-02", q.Get("from"), time.Local)
+		if err != nil {
+			return name, now, now, exclude, fmt.Errorf("bad from date")
+		}
+		to, err := time.ParseInLocation("2006-01-02", q.Get("to"), time.Local)
+		if err != nil {
+			return name, now, now, exclude, fmt.Errorf("bad to date")
+		}
+		to = to.AddDate(0, 0, 1) // include the whole last day
+		if !to.After(from) {
+			return name, now, now, exclude, fmt.Errorf("from must be before to")
+		}
+		return name, from, to, exclude, nil
+	}
+
+	d, ok := reportRanges[name]
+	if !ok {
+		return name, now, now, exclude, fmt.Errorf("unknown range %q", name)
+	}
+	return name, now.Add(-d), now, exclude, nil
+}
+
+func buildReports(from, to time.Time, exclude bool) []common.UptimeReportType {
+	reports := []common.UptimeReportType{}
+	for _, t := range common.GetConfig().AllTargets() {
+		results := common.GetHistoryRange(t.Name, from, to)
+		reports = append(reports, common.UptimeReport(t.Name, results, from, to, exclude))
+	}
+	return reports
+}
+
+func cachedReports(name string, from, to time.Time, exclude bool) []common.UptimeReportType {
+	return cachedReport(reportKey("reports", name, from, to, exclude), func() interface{} {
+		return buildReports(from, to, exclude)
+	}).([]common.UptimeReportType)
+}
+
+func formatSeconds(s float64) string {
+	return (time.Duration(s) * time.Second).String()
+}
+
+// buildReportRows works out the uptime table: each target over the fixed
+// ranges and the selected one
+func buildReportRows(name string, from, to time.Time, exclude bool) []map[string]interface{} {
+	// the short ranges are worked out from the same 30 days of history
+	now := time.Now()
+	longest := now.Add(-reportRanges["30d"])
+	rows := []map[string]interface{}{}
+	for _, t := range common.GetConfig().AllTargets() {
+		recent := common.GetHistoryRange(t.Name, longest, now)
+		selected := recent
+		if name == "custom" {
+			selected = common.GetHistoryRange(t.Name, from, to)
+		}
+
+		report := common.UptimeReport(t.Name, selected, from, to, exclude)
+		rows = append(rows, map[string]interface{}{
+			"target":   t.Name,
+			"day":      common.UptimeReport(t.Name, recent, now.Add(-reportRanges["24h"]), now, exclude).UptimePercent,
+			"week":     common.UptimeReport(t.Name, recent, now.Add(-reportRanges["7d"]), now, exclude).UptimePercent,
+			"month":    common.UptimeReport(t.Name, recent, longest, now, exclude).UptimePercent,
+			"selected": report.UptimePercent,
+			"downtime": formatSeconds(report.DowntimeSeconds),
+			"outages":  report.Outages,
+			"mttr":     formatSeconds(report.MTTR),
+			"mtbf":     formatSeconds(report.MTBF),
+		})
+	}
+	return rows
+}
+
+func reportsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name, from, to, exclude, err := reportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows := cachedReport(reportKey("rows", name, from, to, exclude), func() interface{} {
+		return buildReportRows(name, from, to, exclude)
+	})
+
+	slos := []map[string]interface{}{}
+	for _, report := range cachedSLOReports() {
+		slos = append(slos, map[string]interface{}{
+			"target":    report.Target,
+			"label":     report.SLO.Label(),
+			"window":    report.SLO.Window().Hours() / 24,
+			"good":      report.GoodPercent,
+			"remaining": 100 * report.BudgetRemaining,
+			"burnRates": report.BurnRates,
+			"alerting":  report.BudgetRemaining < 0,
+		})
+	}
+
+	templateArgs := map[string]interface{}{
+		"rows":         rows,
+		"slos":         slos,
+		"range":        name,
+		"from":         from.Format("2006-01-02"),
+		"to":           to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"exclude":      exclude,
+		"query":        r.URL.RawQuery,
+		"cacheMinutes": int(reportCacheFor / time.Minute),
+	}
+	if name != "custom" {
+		templateArgs["to"] = to.Format("2006-01-02")
+	}
+	render(c, w, "reports", templateArgs)
+}
+
+func reportsJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name, from, to, exclude, err := reportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cachedReports(name, from, to, exclude))
+}
+
+func reportsCSVRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	name, from, to, exclude, err := reportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=uptime.csv")
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"target", "from", "to", "checks", "uptime_percent", "downtime_seconds", "outages", "mttr_seconds", "mtbf_seconds"})
+	for _, report := range cachedReports(name, from, to, exclude) {
+		out.Write([]string{
+			report.Target,
+			report.From.Format(time.RFC3339),
+			report.To.Format(time.RFC3339),
+			strconv.Itoa(report.Checks),
+			strconv.FormatFloat(report.UptimePercent, 'f', 3, 64),
+			strconv.FormatFloat(report.DowntimeSeconds, 'f', 0, 64),
+			strconv.Itoa(report.Outages),
+			strconv.FormatFloat(report.MTTR, 'f', 0, 64),
+			strconv.FormatFloat(report.MTBF, 'f', 0, 64),
+		})
+	}
+	out.Flush()
+}
+
+func buildSLOReports(now time.Time) []common.SLOReportType {
+	reports := []common.SLOReportType{}
+	for _, t := range common.GetConfig().AllTargets() {
+		histories := map[time.Duration][]common.CheckResultType{}
+		for _, slo := range t.SLOs {
+			window := slo.Window()
+			if _, ok := histories[window]; !ok {
+				histories[window] = common.GetHistoryRange(t.Name, now.Add(-window), now)
+			}
+			reports = append(reports, slo.Report(t.Name, histories[window], now))
+		}
+	}
+	return reports
+}
+
+func cachedSLOReports() []common.SLOReportType {
+	return cachedReport("slos", func() interface{} {
+		return buildSLOReports(time.Now())
+	}).([]common.SLOReportType)
+}
+
+func sloJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cachedSLOReports())
+}
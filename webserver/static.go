@@ -1,9 +1,21 @@
 // Code generated by go-bindata.
 // sources:
+// templates/audit.html
 // templates/bottom.html
 // templates/config.html
+// templates/configeditor.html
+// templates/configversions.html
+// templates/history.html
 // templates/home.html
+// templates/incident.html
+// templates/incidents.html
+// templates/login.html
+// templates/reports.html
+// templates/statuspage.html
+// templates/target.html
+// templates/tokens.html
 // templates/top.html
+// templates/users.html
 // DO NOT EDIT!
 
 package webserver
@@ -71,6 +83,26 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _templatesAuditHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x84\x54\x51\x6b\xdb\x3c\x14\x7d\x4e\x7e\xc5\x45\x4f\xdf\x07\xab\xed\x86\x76\x0f\x45\x36\x14\xba\x8c\xc2\x36\xca\x96\xbd\xec\x4d\xb5\xaf\x6d\x81\x2c\x19\xf9\xba\xeb\x10\xfe\xef\x43\x52\x1a\x3b\x4d\x46\x9e\x92\x7b\xee\xf1\x39\xba\xba\x07\x39\x07\x15\xd6\x52\x23\x30\x31\x56\x92\x18\x4c\xd3\x7a\xe5\x1c\x61\xd7\x2b\x41\x08\x8c\x4c\xcf\x20\x99\xa6\xf5\x7a\xc5\xdb\x4d\x71\xef\x59\xa0\x4c\xc3\xd3\x76\x53\x78\xb0\x36\xb6\x03\x51\x92\x34\x3a\x67\xe9\x5e\xa5\x43\x6a\x4d\x95\xb3\xcf\x9f\x76\xac\x58\xaf\x56\x5c\xea\x7e\x24\xa0\x3f\x3d\xe6\x8c\xf0\x95\x18\x68\xd1\x61\xce\x44\x49\xc6\x32\xe8\x95\x28\xb1\x35\xaa\x42\x7b\xc0\x5e\x84\x1a\x31\x67\xce\x41\x52\x4b\x45\x68\xef\x3d\x0e\xd3\x74\x51\x51\x1a\x7d\x2a\x29\x8d\xfe\x00\x98\x34\x09\x94\x46\xd7\xb2\x01\x63\x61\x1c\xd0\x26\xca\x34\x52\x9f\xb7\x93\x46\x5f\xf4\x1b\xa4\x2e\xf1\x9d\x5d\xc0\xf6\x6e\x9b\x2c\xfb\x78\x95\x5d\x5f\x65\x9b\xdd\xf5\xed\x5d\x76\x73\x97\xdd\xfe\x3a\x72\x0b\xe4\x8b\x36\xa3\x26\xa9\xde\xd9\xec\xb1\x85\x56\x40\xce\x6a\x0d\xe3\x73\x27\xe9\x40\xde\x86\x11\x3d\x8d\xa7\x7e\x83\x7e\x95\xce\x81\xac\x21\xc1\x17\xd4\x34\x84\x20\x70\x12\xcf\x0a\x83\x16\xd9\x82\x53\x5b\xec\x64\x87\x3c\xa5\x36\x14\x61\x21\x87\xea\xf1\x69\xd9\x90\x46\x1f\xca\x07\x24\x21\x55\x2c\x53\xb2\x5e\xcf\x39\xb0\x42\x37\x78\x64\x17\x5c\xd6\x2b\xff\x5b\x15\x7e\x9c\x7b\x4a\xb6\xc6\x76\x82\x80\xcd\xd7\x08\x6f\xd7\x08\x5f\x7f\xec\x7c\x60\x79\x4a\xd5\xf1\x67\xfb\xa0\x9c\x34\x1e\x9f\x60\x9a\xf6\x63\x6e\x8d\xfd\x2d\x6c\x85\xd5\x36\x70\xe1\xbf\xda\x58\x70\xee\xa4\xf1\xbf\x73\x80\xba\xfa\x87\x4f\x4c\xc8\x49\x27\x4e\xbc\xe8\x2c\xe6\xf6\xe6\x0f\xb2\xae\xe7\x91\xfd\x57\x81\xc8\xa9\x82\xd2\xa8\xa1\x17\x3a\x67\x37\xac\xe0\xbd\xc5\xa8\x17\xf9\x3c\xf5\x40\xa4\xce\x82\xf1\x74\xc7\xff\x79\xfa\xb6\x3a\x0f\xaa\x01\x83\xad\xc6\x57\x8a\xed\xbe\xf8\x66\xa8\x95\xba\x81\x4e\x50\xd9\xe2\x00\x52\x03\xb5\x08\xa8\xc9\x4a\x1c\x60\x40\x61\xcb\x16\x2b\x18\x0c\xd4\xc2\xf2\xb4\x5f\x68\x9d\x97\x98\x39\xf1\x10\x87\x44\x2d\x6d\xb9\x80\xd6\x62\x1d\xe3\x1a\x72\xfc\xf3\xfb\x97\x90\xd8\x50\xf0\x54\x14\xe7\x74\xe6\x07\xe9\xd9\x10\x99\x2e\xbe\x49\x33\xe7\xef\x00\x7b\x40\x39\xda\xc9\x04\x00\x00")
+
+func templatesAuditHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesAuditHtml,
+		"templates/audit.html",
+	)
+}
+
+func templatesAuditHtml() (*asset, error) {
+	bytes, err := templatesAuditHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/audit.html", size: 1225, mode: os.FileMode(436), modTime: time.Unix(1792365698, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
 var _templatesBottomHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xaa\xae\x56\x48\x49\x4d\xcb\xcc\x4b\x55\x50\x4a\xca\x2f\x29\xc9\xcf\x55\x52\xa8\xad\xe5\x52\x50\xb0\xd1\x4f\xca\x4f\xa9\xb4\xe3\xb2\xd1\xcf\x28\xc9\xcd\xb1\xe3\x02\xaa\x4b\xcd\x4b\x01\xca\x01\x02\x00\x00\xff\xff\x8a\xda\x87\x4b\x31\x00\x00\x00")
 
 func templatesBottomHtmlBytes() ([]byte, error) {
@@ -91,7 +123,7 @@ func templatesBottomHtml() (*asset, error) {
 	return a, nil
 }
 
-var _templatesConfigHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6c\x50\xcb\x4e\xc3\x40\x0c\x3c\x27\x5f\x61\xf9\x03\x9a\x0a\x2e\x1c\x36\x7b\x29\x9c\x41\x2a\x3f\xb0\xd9\x38\x74\xa5\xec\x43\x1b\xa7\x80\xa2\xfe\x3b\xde\xb4\x55\x01\xf5\x66\x8f\xc6\x33\xe3\x59\x16\xe8\x69\x70\x81\x00\x6d\x0c\x83\xfb\x40\x38\x9d\xea\x6a\x59\x98\x7c\x1a\x0d\x0b\xce\x31\x21\x6c\x04\xad\x2b\x75\x78\xd0\x2f\xbd\x63\xd8\xad\x5c\xd5\xc8\x5e\x17\x36\xb8\x01\x36\xd3\x6c\x2d\x4d\xd3\x2a\x50\xa9\x04\x76\x34\xd3\xd4\xe2\x05\x46\x2d\xb4\x5f\x1c\xd5\x24\xbd\x9e\x52\xe8\xcb\x49\x91\x1f\x62\xf6\x60\x2c\xbb\x18\x5a\x6c\xae\x81\x3c\xf1\x21\xf6\x2d\xbe\xbd\xee\xdf\x51\x17\x6d\xa6\x2f\x36\x99\x0c\x04\xe3\xa9\xbd\x24\x7f\x36\x6c\x10\x6c\x1c\xc5\xf3\x69\x8b\x90\xe3\xa7\x4c\x8f\xdb\xb3\xf1\x8d\xb3\x7a\x5f\x15\xb4\xea\xb2\xae\x01\x44\xd4\x85\x34\x33\xf0\x77\xa2\x92\xb9\xf3\x8e\x11\x8e\x66\x9c\x65\xdd\x9b\x23\xe1\x4a\x53\x4d\xc9\x28\x4f\x97\x59\xbe\xdf\xcd\x39\x53\xf8\x5f\x88\x4a\x99\xee\xb9\x16\xb8\xfe\xdb\x6e\x17\x99\xa3\x3f\x17\x7c\x2b\xe3\x27\x00\x00\xff\xff\x30\x37\x14\xec\x97\x01\x00\x00")
+var _templatesConfigHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x64\x53\xc1\x6e\xdb\x30\x0c\x3d\xdb\x5f\x41\x08\x03\x06\xec\x10\x17\xdd\x65\x28\x6c\x5d\xba\x5d\x86\x75\x2d\x90\xde\x07\x46\xa2\x6b\xa1\xb2\x64\x88\x72\xb2\xc2\xf0\xbf\x0f\x92\xed\xb4\xc9\x72\x89\xfd\x48\x3e\xbe\x47\xd2\xd3\x04\x9a\x5a\xe3\x08\x84\xf2\xae\x35\x2f\x02\xe6\xb9\x2c\xa6\x29\x52\x3f\x58\x8c\x04\x22\xfa\x41\xc0\x6e\x9e\xcb\xb2\xa8\xbb\x5b\xf9\x43\x9b\x08\xf7\x39\x17\x90\xe1\xe7\xfe\xf1\x77\x5d\x75\xb7\x32\x85\x07\xf9\xdc\x19\x06\xc3\x10\x3b\x02\xd4\x47\x74\x8a\x34\x90\x36\xd1\x87\x1d\x3c\x78\x8e\xa0\x3a\x74\x2f\xc4\x80\x81\x80\x90\x0d\x05\x30\x2e\xe7\xd7\x08\x5d\xa0\xb6\x11\xd5\x2a\x45\x2e\xff\xd0\xfa\xd0\x73\x5d\xa1\xdc\xd5\xd5\x20\x73\x9f\x3d\xa9\x40\x91\x81\x3b\x7f\x4a\x32\xbe\xac\xbf\x1d\xfc\x22\x3c\x52\xe2\xeb\xc1\x9a\xd7\xf4\x84\x11\xa2\x87\x57\xa2\x21\xc3\x0b\xc9\xa5\xc7\xa5\x11\xfb\x31\x28\xda\xcc\x4e\x13\x98\x16\x76\x3c\x2a\x45\xcc\x79\x2c\x45\x3d\x80\xb2\xc8\xdc\x88\x15\x16\x72\x9a\x3e\xe6\x2c\x02\xa7\x09\xc8\x69\xf8\x48\x43\x21\xf8\xf0\x1f\x4b\x46\x85\x7c\xee\x08\x56\xaf\x27\x64\xf7\x39\x02\xe3\x91\xf4\xdd\xc2\x56\xd4\xa3\xbd\x2a\x28\x8b\xc4\x1b\xd2\x24\x2f\xa9\x8b\xda\x1a\xb9\xb6\x7c\xc2\xd8\x25\x49\xca\x6b\xca\x32\x37\xa0\xca\xc8\x1d\x9c\x65\xa6\xe0\x03\x31\xe3\x0b\xe5\xb8\x35\x6b\x87\xd5\x45\x51\xd4\xd5\x68\x2f\x8d\x95\x45\x9d\xf6\x02\xa8\xa2\xf1\xee\xbc\xb4\x2a\xe0\x49\x40\x4f\xb1\xf3\xba\x11\x4f\x8f\xfb\xe7\xac\xb6\x36\x6e\x18\x23\xc4\xb7\x81\x1a\xd1\x19\xad\xc9\x09\x70\xd8\x53\x23\x14\x87\xf6\x4f\xf4\xaf\x09\x39\xa2\x1d\xa9\x11\xd3\x04\x9f\x76\x09\x87\x79\x5e\xca\x5b\x43\x56\x33\xc5\xd5\x5a\x20\xd4\x8f\xce\xbe\xc1\x3c\x83\x36\x8c\x07\x4b\xfa\xac\x2d\x17\x44\xfa\x1b\x31\x10\x6e\x4d\xb2\xb8\xef\x18\x51\x80\xf2\x96\x1b\xf1\xed\x46\x40\xf0\x27\x6e\xc4\xd7\x9b\x65\x8b\xef\x39\x79\x08\x1b\x83\xac\x0f\x41\x96\x00\x57\x26\x78\x3c\xf4\x26\x9e\x25\xef\xf1\x48\x8b\xd4\x6a\xd3\x9a\x8b\xea\x2a\x4d\x49\x96\xf9\x79\x90\xd7\x37\x5e\x1d\x29\xb0\xf1\x8e\x85\xf4\x56\x53\x80\xed\x3d\x9d\xfb\x72\xa8\xa9\xb0\xbb\x95\xf7\x63\x08\xe4\xb6\x4f\xef\xfd\x93\x0b\xcb\x6e\xd5\x1a\xce\x27\x18\xe8\xfa\xc0\x0f\x3e\x46\xdf\x2f\xa7\xfd\xbe\xc4\x7f\x03\x00\xb9\x64\x44\xf7\xfe\x03\x00\x00")
 
 func templatesConfigHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -106,12 +138,72 @@ func templatesConfigHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/config.html", size: 407, mode: os.FileMode(436), modTime: time.Unix(1442355067, 0)}
+	info := bindataFileInfo{name: "templates/config.html", size: 1022, mode: os.FileMode(436), modTime: time.Unix(1792363474, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesConfigeditorHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xd4\x58\xdf\x6f\x1b\xb9\x11\x7e\xb6\xff\x8a\x01\x11\xa0\x39\x9c\x6f\x15\xc7\x71\x8a\x1c\x56\x5b\x1c\x9c\x04\x97\xa2\x48\x82\xb3\xdb\x3e\x5c\x83\x82\x5a\x8e\xb4\x84\xb9\xe4\x96\x9c\x95\x6c\xa8\xfa\xdf\x0b\x92\xfb\xdb\x92\x63\x19\x7d\x68\x5f\x6c\x71\x38\x1c\x0e\xbf\xef\x1b\x92\xcb\xed\x16\x04\x2e\xa5\x46\x60\xb9\xd1\x4b\xb9\x42\x21\xc9\x58\x06\xbb\xdd\xe9\xc9\x76\x4b\x58\x56\x8a\x13\x02\x23\x53\x31\x48\x76\xbb\xd3\xd3\x93\xb4\x78\x9d\x5d\x05\xe7\x74\x56\xbc\xce\x4e\xc7\x8e\x31\x8c\x33\xb5\xcd\xb1\x1d\xb1\xdd\x82\x5c\x42\xe2\xea\x3c\x47\xe7\x60\xb7\x4b\x2b\xc8\x15\x77\x6e\xce\x1a\x1b\xcb\xb6\xdb\x91\xc3\xac\xf2\x16\xd4\xa2\xc9\x24\x04\x40\x6b\x8d\x75\xc1\x92\xd6\xaa\x0d\x11\xac\x2c\x3b\x3d\xf1\x6e\x96\xeb\x15\x0e\x3c\x53\x25\x43\xe8\x10\x53\xc9\x61\xd0\x74\x56\xab\xec\xf4\xa4\xb7\xf8\xa5\x5d\x64\x37\xdc\xae\x90\x5c\x3a\x2b\x2e\xb2\x3e\x77\x8a\xd6\x38\x90\xf8\x42\xa1\x9f\x30\x25\x9b\xa5\x54\x64\x9f\x79\x89\xe9\x8c\x8a\xd0\xf8\xeb\x6f\x7f\xe9\x7e\x7f\x58\xa3\xbd\xef\x5a\x37\x7c\xe5\xba\x46\xfc\x31\x23\x3b\xce\x7c\x38\x51\x88\x7f\x7a\xe2\xff\x8b\x2c\xe5\x50\x58\x5c\xce\xd9\x2c\x42\x3c\x8b\x9e\x7f\xd2\xbc\xc4\xb9\x5f\xa2\xff\x01\xbb\x1d\xcb\x06\x8d\x74\xc6\xfd\x1c\xa2\x8b\xe2\xfb\x6a\xab\x42\xd7\xc4\x8c\x3e\xd7\x7d\x1d\xc4\x57\x6e\x6a\xf7\xff\x1b\x6c\xb4\x21\x78\x91\x58\xe4\xe2\x8b\x56\xf7\x31\xf1\x93\x93\x74\x69\x6c\x09\x3c\x27\x69\xf4\x34\xe7\x99\x40\x85\x84\x0c\x4a\xa4\xc2\x88\x39\xfb\xfa\xe5\xfa\x86\xc5\x98\x27\xa9\xd4\x55\x4d\x40\xf7\x15\xce\x59\x21\x85\x40\xcd\x20\x2c\x92\xe5\xce\x2e\xff\x49\xe6\xd6\x5b\xd6\x5c\xd5\x38\x67\xdb\x2d\xbc\x48\xbc\x3d\xac\xfc\xbb\x11\xfc\xdf\xe1\xd8\x1e\xb4\x3d\x43\x5d\xbd\x28\x25\x75\xee\x4d\xd2\xd1\x33\x9d\xf9\xf5\x75\x30\xb4\x9a\xf2\x1d\x11\x9d\x01\xb3\xbd\xe0\x5a\xe1\x78\xa3\x72\x18\xad\x55\xf6\xd9\x40\x4b\xfb\x3d\x92\xd7\xfe\x50\x96\x03\x98\x87\x28\xa7\xd5\x21\x49\xb0\xec\x17\x21\x80\x37\x31\xa3\x04\xaa\x6c\xaa\xf3\xbf\x17\x06\xc2\x94\x39\x57\x0a\x45\xab\xf7\xfd\xbc\xe5\x46\x13\xcf\xc9\xed\xa1\xec\x68\xbe\x46\x74\xa5\x4b\x89\x4a\x38\xa4\xa6\xce\x06\x0b\x04\x21\x9d\xc7\x4b\x74\x99\x87\x01\x5d\xf1\xf5\x45\xf3\x42\x9e\xc1\x0b\x6b\x36\xf0\xf3\x1c\x92\x36\xd5\x96\x8f\xa6\x84\x62\x0d\x0d\x73\x25\x54\x6d\xa2\x55\x61\xf4\x48\x17\x3e\x5a\xf2\xd5\x5b\x7d\xa2\x50\x29\x9e\x63\x61\x94\x40\x3b\x67\x3f\x9e\x5f\x5e\x5e\x9e\xbf\xbe\x78\x73\xf9\xf6\x8f\x0c\x2a\x4e\x84\x56\xcf\xd9\x3f\x7e\xfc\xfd\xfc\xa7\x77\xdf\x7e\x7f\xf5\xd3\xbb\x6f\xdb\xf3\xb3\xf3\x37\x3b\x06\x24\x49\xe1\x9c\x7d\x48\xce\xdf\xbe\x39\x03\x25\x6f\x11\x46\xa3\xfb\xda\x6c\x8b\x4d\x2e\xc7\x73\xa7\x8a\x2f\x50\x8d\x13\xcf\x0b\xcc\x6f\x17\xe6\xae\xcd\xde\x62\x69\xd6\xe8\xb3\x96\x21\xdb\x66\x19\xe7\x2c\x83\xd8\x95\xce\x62\x94\x0e\xc8\x83\xd3\x7e\xf0\xfb\xa6\x77\x70\x15\xd7\x93\x0d\x76\xbb\x9d\xf8\xcc\xbc\xd3\x9e\xa0\xad\xf4\x47\x85\xd1\x8b\x7f\x7f\x91\xc5\xa5\x70\x21\xba\xfc\xbd\x88\xad\xd9\x30\xf0\x8a\xd4\x66\xcd\x95\x14\x9c\x0e\x47\x68\x86\x5d\xf3\x35\x42\xa7\xd7\x58\x8a\xad\xc8\xb2\xd3\xae\x70\x63\x11\x5c\x23\x91\xd4\x2b\xf7\xb8\xfa\x5d\xe3\xf5\x3f\xa5\xfe\xe6\xec\x81\xdc\x28\xcf\xc3\x9c\x5d\xb0\xec\xca\x2b\xc3\x8d\x4e\x96\x49\x01\x04\x21\x78\x44\xe7\xec\xab\xd4\xab\x8f\x16\xff\xc5\xb2\xf6\x57\xab\x93\xb1\x3c\x46\x4b\xd4\x75\xb9\x40\xcb\xa0\x94\x3a\x2e\xa7\x94\xfa\x93\x26\xb4\x6b\xae\x82\xf8\x4a\x7e\xd7\x74\xf0\xbb\x51\x87\x14\x83\x29\x1b\x88\xfa\xf6\x00\xa0\x16\xed\xa4\xed\x0d\x68\x3d\x90\xec\x46\x52\x01\x52\x0b\xbc\xeb\x87\x7c\x88\xc7\xfe\x20\xee\x41\x29\x27\x63\x05\xc7\xdd\xd8\x61\x6e\xb4\x70\xb0\x40\xda\x20\x6a\x08\x95\xe6\xce\x3c\x5e\xdd\x0e\x4d\x05\x27\x10\x46\xff\x81\xc0\x21\x01\x15\x28\x2d\x98\x8d\x4e\xe0\xed\x2b\x4f\x22\x96\x15\xdd\x3f\x52\x14\x07\xf9\xf8\x55\x3a\x32\xf6\xfe\x3d\xbf\x77\x2c\x1b\x34\x8e\x65\xe5\x55\xc4\x7a\x18\xae\x81\x7b\x64\xda\x87\xf8\xc0\xe1\x78\xd0\x47\xd1\x8f\xc3\x5d\xf8\x09\x03\xd8\x60\xd1\xd5\x8a\x1c\x70\x8b\x70\x8b\x15\x25\x70\xd1\xc3\x7a\x06\x5c\x0b\xe0\x04\x0a\xb9\x23\x78\xf7\x6a\xc4\x8c\xd1\x9e\x0d\x70\xc4\xa9\x76\x50\xf1\x15\x3e\x87\x86\x78\xb5\xfd\x1b\x5a\x27\x8d\x76\x2c\x1b\xb7\x9f\x49\xc6\x24\x68\xc3\xc7\xd4\xba\x8f\x92\xb1\xcf\xf1\xac\x4c\xe7\x38\x8e\x18\xa3\x04\x34\x37\xfa\xc0\x46\xc0\xdb\x1a\xa5\x16\x3c\xbf\x4d\xe0\xf2\xc9\x8a\x9f\xee\x54\xf1\x7c\xf3\x57\x0f\x07\x2f\x6f\x36\x52\x49\xf3\xc3\x13\xf7\xad\xe8\x7d\xfd\xe9\x3d\xcb\xba\x9f\x4f\xa0\x85\xf0\x8e\x22\x19\x7d\x80\x86\x87\x81\x61\x1f\x05\x5d\xf7\xf1\xe8\x0f\x22\x1f\x07\x3c\xcf\x73\x53\x6b\x82\xeb\x4f\xef\x9f\x23\xe2\x38\xef\x2f\x35\x15\x37\xe1\x00\xca\x26\x86\x27\xe0\x55\x71\xe7\x36\xc6\x8a\x21\x66\x7d\xc0\x11\x72\x03\xf3\x61\xfc\x3a\xa7\x70\x1a\xf0\x9a\x4c\x6e\xca\x4a\x21\xe1\x9c\x99\xe5\xf2\x59\xc0\x0e\x26\x3e\x12\xde\x9a\x0a\x08\x67\x73\x02\x1b\x2b\x09\xc1\x68\x75\xff\xb3\xdf\x57\xd6\x18\x76\x11\x61\xc8\x01\x19\xb8\x45\xac\x40\xd2\x19\xe4\x0a\xb9\xf5\x5d\xa5\x37\xc7\x8b\x15\x48\x7a\x3e\x3b\x57\x5c\xa9\x8f\xd6\x94\x2c\x1b\xb7\x9f\xa4\x65\x35\xa4\xa5\x8b\x34\x62\xa5\xb7\x1e\x26\xa5\xf5\x79\xae\xb2\xfb\x39\x8e\xc3\x3f\xee\x91\x4d\xfd\xe7\xa6\x44\x58\x5a\x53\xee\xb9\x22\x1f\xb9\xad\x5c\xf9\xc3\xf9\xe5\xaf\xb2\xca\x0b\x4e\x4f\xdd\x50\x1a\xf7\x41\xb5\x4c\x2d\x47\x97\xcb\x83\x90\xdd\x09\x3c\xb5\xef\x3f\x86\xc7\x5e\xff\x95\x8a\x79\x38\xf5\xff\x53\xc9\x34\xd9\xff\x66\x7c\xbd\x0c\x1a\x47\x6d\xfc\xc3\x20\x63\x42\xa2\xe9\x11\x2e\xbc\xc3\x73\xae\x44\x83\xe8\xc7\xc1\x6d\x8d\x29\x0f\x03\xf5\xbd\xaf\xa9\xe1\xb7\x50\xf7\xf5\xf2\xd8\xb7\xd0\x9e\xc7\x04\xcb\x37\x2c\x7b\xf8\x61\xb2\x96\xb8\xe9\xd3\x44\x31\xe0\x33\xa8\xc0\xf2\x0d\xfc\xf9\xfa\xcb\x67\xff\xee\x00\x2f\xb9\x58\x73\x9d\xa3\xf8\x01\xfe\x0d\x0f\x66\x58\x77\x97\xac\xf0\x69\x0d\xeb\xee\x92\x15\x9f\x2c\x26\x4f\x8b\x0b\x43\x64\xca\xf8\xa8\x38\x78\xcd\x78\xf0\x86\xd9\x3e\x3e\x0e\x5e\x0e\x47\xaf\x53\x69\x35\xe1\xe0\xa6\xc0\xe6\x96\x03\xd2\x41\xc9\x35\x5f\xa1\x00\xa9\x21\xcd\x8d\xc0\x40\x51\xec\xfe\x28\x55\x7c\x55\x0b\xf6\x70\x19\xcd\xb9\xff\x12\x58\x20\xe4\x85\x7f\x8b\x10\x50\xa0\xc5\x04\xae\x42\x2b\x00\xb2\xf4\x83\xbc\xab\xab\x2b\xa8\x64\x7e\xeb\x40\x12\xd4\x55\xd2\x3f\xf4\x78\x2c\xe5\x72\x32\x4b\x60\x65\x9c\x9a\x32\x5c\xa0\x08\x7b\xe5\x77\x72\x6b\x33\xf0\xcb\x11\x18\x92\x0a\x57\x6a\x8b\xe1\x2d\x43\xc0\xa6\x40\xdd\xa7\x17\x93\x77\xc9\xf4\xed\xa9\xff\xf5\x9f\x01\x00\x74\x94\x6b\xde\x2a\x16\x00\x00")
+
+func templatesConfigeditorHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesConfigeditorHtml,
+		"templates/configeditor.html",
+	)
+}
+
+func templatesConfigeditorHtml() (*asset, error) {
+	bytes, err := templatesConfigeditorHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/configeditor.html", size: 5674, mode: os.FileMode(436), modTime: time.Unix(1792364898, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesConfigversionsHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x94\x55\x4d\x6f\xdb\x38\x10\x3d\xdb\xbf\x62\x40\x2c\x16\xc9\x21\x92\xe3\x6c\xf6\x90\x95\xb8\x48\x83\xa6\xe8\xa1\x49\xd1\x04\x3d\xf4\x52\xd0\xd2\x28\x12\x2c\x71\x54\x72\xec\xc2\x10\xf4\xdf\x0b\x52\x1f\xb6\x9c\x34\x6d\x4f\x16\xc9\x79\x8f\x8f\x8f\x6f\xe8\xa6\x81\x14\xb3\x42\x23\x88\x84\x74\x56\x3c\x6d\xd1\xd8\x82\xb4\x15\xd0\xb6\xf3\x59\xd3\x30\x56\x75\xa9\x18\x41\x30\xd5\x02\x82\xb6\x9d\xcf\x67\x51\xbe\x94\x37\xbe\x1c\x86\xfa\x28\xcc\x97\x72\x3e\x45\x74\x8c\x96\x36\x26\xc1\x01\xda\x34\x50\x64\x10\xd8\x4d\x92\xa0\xb5\xd0\xb6\x51\x0d\x49\xa9\xac\x8d\x45\x3f\x27\x64\xd3\x4c\x0a\xc2\xda\xcd\xa0\x4e\x7b\x49\x9e\x00\x8d\x21\x33\x81\xfb\x99\x0e\x3c\x2e\x4e\xa0\x23\x76\xd0\xec\xf9\xa2\x8c\x4c\x05\x2a\xe1\x82\x74\x2c\xc2\x4e\x73\xb8\xb7\xa1\x42\xce\x29\x8d\xc5\xbb\xb7\x8f\x42\xce\x67\x11\xab\x55\x89\x72\x3e\x9b\x45\x6c\x64\xc4\xb9\xbc\x35\x54\x45\x21\xe7\x7e\xf0\x48\xe3\xe7\xe7\x8e\x62\x1c\x3f\xa8\x2d\xa6\xe3\xe8\xcd\x6e\xfc\x3c\xfa\x08\xd9\x38\xfa\xa6\x01\xa3\xf4\x13\x1e\xc9\xf5\xdb\xce\x67\xee\x37\x95\x51\xa1\xeb\x0d\x03\xef\x6a\x8c\x85\x51\x69\x41\x02\xb4\xaa\x30\x16\x99\xa1\x4a\xc0\x56\x95\x1b\x8c\x85\xb3\xa4\x57\x03\x6d\x2b\x3a\x17\xf0\xdb\x7e\xf2\xaf\xc0\xd5\x43\xdb\x42\x92\x63\xb2\xc6\x74\x34\xcd\xc9\x49\x7f\x63\x3f\xa6\x3f\xd9\x8d\xe9\xd7\x7b\x4d\x69\x8e\x97\x8a\x0c\x34\x31\x04\xd7\x1c\xbc\xb7\x5f\xd0\x38\x42\x87\xb8\xe6\xe0\x96\x4c\xa5\x18\xc4\x72\xb1\xf8\xf7\x6c\x71\x7e\xb6\x58\xc2\xf9\xe5\xd5\xe2\x9f\xab\xc5\x25\x7c\x78\x78\x14\x5d\x25\x96\x16\xa1\x6d\x57\x98\x91\xc1\x31\xc5\xf0\x1d\x0d\xc2\x1a\x6b\x1e\x55\x3d\x13\x75\xbd\xe1\x9c\xcc\x4b\x2b\x77\xc4\xf8\x13\xad\x78\x78\x7c\xd7\x1d\x96\x5d\xa5\x82\xdc\x60\xf6\x3c\x76\xff\xbb\xfb\x88\x9b\xe6\xb0\xf8\x6f\x55\xd5\xff\x31\xc5\x47\xfe\xca\x24\x77\x31\xb1\xc0\x04\x4f\x04\x2b\x95\xac\xa3\x50\xc9\x67\xfa\x0f\x72\x35\xb4\x52\x14\x0e\x61\x9e\x5c\xac\xdd\xac\xaa\x82\xc7\xfb\xbc\xa1\xaa\x56\x06\x7d\xf8\x43\xd7\x2c\x72\xdf\x49\x69\x91\x65\x1d\x55\x7e\x21\x6f\x7a\x21\x3e\x4b\xfd\x49\xc0\xa9\x1d\xc2\xc5\xe4\x87\xfe\xf6\xa3\x30\xbf\x70\x8c\xb5\x41\xef\x5d\xcf\x14\x85\x6e\x62\xdc\x40\xe9\x14\x4e\x9c\x79\x4c\xd0\x3b\x71\x0a\x27\xfe\xe6\x0d\xaa\xf4\x5e\x97\xbb\xd3\x57\xba\xd8\x50\x59\x3a\x43\xf6\x5d\xfc\xf1\xfe\xc1\xb7\xf1\xf4\xc4\x79\x91\xa6\xa8\x87\x2c\x27\xd6\x64\x5f\x99\xd6\xa8\x47\x0f\x9c\x42\x37\xed\x0d\x7f\x1d\xdd\x1f\x7c\x02\x65\x7a\x11\x78\x64\xf4\x27\x2a\x4b\x7f\x7f\xce\xa8\x43\xff\x46\xf8\xe0\xff\x6c\xfa\x1e\x1e\xbe\x6f\x7d\xae\x9d\xb3\xf2\x8e\xf6\xc1\xde\x21\x07\x70\xaf\x11\x0a\xeb\xf3\x0d\xb8\x45\xb3\x03\x2e\x2a\x04\xce\x11\x3a\xc7\xdc\xaa\x75\xef\x54\xe0\x9e\xce\x29\x79\x54\xcb\xe3\xbc\x0a\x89\x69\xc1\x3d\xd6\xa5\xce\xa3\xa6\x7f\x02\x2b\x62\xa6\xaa\x7b\xfe\xf7\x74\x3f\x06\x00\x22\x07\xf9\xee\x78\x06\x00\x00")
+
+func templatesConfigversionsHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesConfigversionsHtml,
+		"templates/configversions.html",
+	)
+}
+
+func templatesConfigversionsHtml() (*asset, error) {
+	bytes, err := templatesConfigversionsHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/configversions.html", size: 1656, mode: os.FileMode(436), modTime: time.Unix(1792363474, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesHistoryHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6c\x52\xc1\x8e\xd3\x30\x10\x3d\xc7\x5f\x31\xf2\x81\xdb\x26\xd9\x88\xe5\xb0\xb8\x46\x08\x81\x10\x62\xe1\xd0\xf2\x01\x6e\x32\x69\x02\x49\x1c\xd9\x53\x89\xca\xf2\xbf\x23\xdb\xa1\x6d\x20\xa7\xc4\xef\xcd\xcc\x9b\x37\x33\xce\x41\x83\x6d\x3f\x21\xf0\xae\xb7\xa4\xcd\x85\x83\xf7\x2c\x73\x8e\x70\x9c\x07\x45\x08\x9c\xf4\xcc\x21\xf7\x9e\xb1\x4c\x74\x95\xfc\x9c\xe2\xa0\xd5\x06\x9c\x83\x9c\x94\x39\x21\x81\xf7\xa2\xe8\x2a\x19\x82\x48\x1d\x07\x94\x2c\xcb\x04\x19\x29\xa8\x93\x87\x7e\x44\x51\x50\x17\x1f\x5f\x75\xad\xa8\xd7\xd3\x15\xd8\x93\xa2\xb3\xbd\xf1\x8a\x70\xaa\x2f\x2b\xfa\x96\xfd\xd1\x18\x6d\xd2\xab\x20\x13\x44\x9c\x03\xa3\xa6\x13\x42\x6e\xd0\x9e\x07\xb2\xd1\x40\xd4\x66\x59\xf8\x36\x32\xb4\xf9\x9e\xf2\x4f\xda\x8c\x8a\x80\x57\x65\xf9\xe6\xa1\x7c\x7c\x28\x2b\x78\x7c\x7a\x2e\x5f\x3f\x97\x4f\xf0\xb2\x3f\xf0\xe8\x81\x9a\x55\xda\xdf\x6e\xff\xe1\xa0\x1e\x94\xb5\x3b\xee\x1c\xf4\x2d\xe4\x3f\x66\xf0\x1e\xec\xb9\xae\xd1\xda\x30\x15\x1c\x2c\x06\x08\x43\xbb\x11\x98\x1a\xf0\x9e\xc7\x9a\xc9\xf0\x07\xdd\xe0\xa6\x62\xf2\xff\x12\x7c\x8c\xf6\x3f\x3a\x8e\x63\x2b\x2f\x4e\xe6\x8e\xb8\x9b\x4f\x12\x67\x01\x4a\xab\x61\xd9\xd2\xf8\x84\xbf\x29\x51\xb3\x14\x0a\x3a\x83\xed\x8e\x17\xcb\x29\xbc\x4b\xab\xdd\xad\xb6\xfc\x4a\x8d\xf3\xdb\x23\xb6\xda\x60\x24\x96\x0a\x11\xb6\xbf\xfa\x39\x82\xe1\x27\xda\xd5\x43\x83\x46\x14\x4a\x8a\x62\x96\xec\xae\x97\x6d\xc5\xfc\xa7\xd5\xd3\xa6\x2c\x97\x5f\xf6\xdf\xbf\xc1\x12\x77\xad\xb8\xbe\xd4\xa3\x26\xd2\x63\x3a\xd6\x9b\xd4\x9f\x01\x00\x27\xe4\x20\x15\xe4\x02\x00\x00")
+
+func templatesHistoryHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesHistoryHtml,
+		"templates/history.html",
+	)
+}
+
+func templatesHistoryHtml() (*asset, error) {
+	bytes, err := templatesHistoryHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/history.html", size: 740, mode: os.FileMode(436), modTime: time.Unix(1792365003, 0)}
 	a := &asset{bytes: bytes, info:  info}
 	return a, nil
 }
 
-var _templatesHomeHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x74\x52\xcb\x6e\xab\x30\x10\x5d\xdf\xfb\x15\x23\x7f\x00\xe4\xde\xec\x22\xe2\x2a\x4d\xb2\x68\x85\xda\x4a\xf4\x07\x1c\x32\x04\x4b\xc6\x76\xed\x61\x85\xf2\xef\xb5\x71\x52\xa0\x6a\x57\x30\xc7\xe7\xcc\x99\xd7\x30\xc0\x19\x1b\xa9\x11\x58\x6b\x3a\x64\x70\xbd\xfe\xfd\x33\x0c\x84\x9d\x55\x82\x02\x4a\xc6\x32\xc8\x02\x1a\x61\x90\x0d\x68\x43\x90\xa1\x16\x27\x85\xe7\xc0\x86\xa2\x5d\x43\xad\x84\xf7\x5b\x86\xce\x19\xc7\xf8\x7e\x57\x96\x15\x1c\x9e\xaa\xdd\x63\x79\x3c\x14\x79\xbb\xe6\x10\xb4\xa8\x23\x3f\xe4\x29\x2c\x0f\x61\xd6\x3b\x15\xe2\x22\xb7\x3c\x61\xa5\xf0\x04\x9e\x04\xf5\x7e\x03\x85\xb7\x42\xdf\xf3\x26\x63\xfc\x80\x2c\xc4\x54\x8d\x14\xf8\xbf\x5a\x45\x7b\xdf\xd7\x35\x7a\x3f\x1a\x28\x8f\x11\x1a\xcb\x98\x1c\xd9\xe8\x36\x53\x46\xd3\x98\x9e\x7f\xf3\xb6\x52\x5f\x80\x64\x87\x1b\xb8\x2b\xde\x02\xf4\x1e\x90\x45\xa1\xf1\x51\xf7\xdd\xde\x68\x12\x35\xc5\x7c\x60\x5b\x13\x46\x18\xc0\x13\x3a\x0f\x26\x54\x2e\x94\x9a\x14\x85\x80\xd6\x61\xb3\x65\xb9\x47\x3a\xa6\xd9\x3d\xdc\x66\xb8\x4d\xdd\xcd\x46\xba\x9a\x7a\xf9\xb7\xec\x62\xc9\x83\xb3\xf4\xf1\x7f\xd1\xbb\xfe\x42\x46\x59\x91\x0b\xfe\x53\x1d\xb5\xd1\x8d\xbc\x30\x9e\xbe\xbd\x13\x24\x8d\xfe\x8d\x9c\x96\xc2\xf8\x73\xf5\xfa\x72\xdb\xd0\x8c\x3a\xbf\x96\x93\x21\x32\x5d\x3a\x98\x69\xe5\x9f\x01\x00\x00\xff\xff\xd2\x33\xc4\xe8\x65\x02\x00\x00")
+var _templatesHomeHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xa4\x57\x4b\x8f\xdb\x36\x10\x3e\xdb\xbf\x62\x40\x04\x45\x12\xec\x5a\x5e\xa7\xe9\x21\x95\x19\x6c\x37\x5b\x20\xc5\xe6\x81\xec\xb6\x3d\x16\xb4\x38\xb6\xd8\x48\x24\x4b\x52\x4e\x5c\x57\xff\xbd\x20\xa9\x97\x9f\xd9\xa2\x27\x89\xc3\x99\x6f\xde\x33\xd2\x76\x0b\x1c\x97\x42\x22\x90\x5c\x95\x48\xa0\xae\xc7\xa3\xed\xd6\x61\xa9\x0b\xe6\x10\x88\x53\x9a\xc0\xa4\xae\xc7\x9e\x0c\x62\x09\x52\x39\x98\xa0\x64\x8b\x02\x39\xd4\x35\xa4\xf9\x0b\xc8\x0a\x66\xed\x9c\xa0\x31\xca\x10\x7a\x73\x7d\x77\x77\x0f\x6f\xde\xde\x5f\xff\x74\x77\xfb\x26\x4d\xf2\x17\x14\xb6\x5b\x40\xe9\xf9\xc7\xe3\x51\xea\xbc\x30\x1d\x8f\x46\xa9\x33\xfe\x31\x4a\x5d\x4e\x1f\x98\x59\xa1\x4b\x13\x97\x53\x7f\xbc\x5d\xa3\xd9\x74\xa7\x3b\x66\x1d\x58\xc7\x5c\x65\x77\x69\x5a\xc8\x15\x38\x51\x62\x20\x7b\xac\x68\xe5\xca\xc1\xd3\x02\x25\x4c\x0a\x95\x31\x27\x94\xb4\xcf\xe0\x0a\xea\x7a\xbb\x05\xc3\xe4\x0a\x07\x17\x50\xd7\x1e\x6e\xbb\x85\x89\x7f\x4d\xe2\x7b\xb4\xb6\x37\x7b\x34\x4a\x93\x68\x6d\x0f\xe1\x82\xc9\xb6\xb9\xee\x7c\xe1\xe0\x84\x2b\x70\x4e\x3c\x64\x65\x0a\xa8\x6b\x12\xe0\x25\x2b\x31\x82\x8a\x25\x4c\x96\x05\xd3\xc1\x7c\x1f\x44\xab\x99\xdc\x0b\xe3\xd3\x96\xe1\x59\x9a\xf8\xeb\xde\xaa\x34\x71\xbc\xd5\x15\x80\xd1\x07\x6b\x78\xd1\xa8\xd0\xac\xb2\xd8\x98\x1f\x98\x1b\xc2\x53\x9b\xe5\xc8\xab\x02\x9f\x0d\x25\xb0\xb0\x18\xc4\xd6\x82\xf5\x32\x7b\x56\x79\x75\x05\xb3\xee\x3e\x24\x03\xea\xfa\x02\x2a\x69\x90\x65\xb9\x4f\x2a\xf0\x0a\xc1\x29\xd0\xcc\xa0\x74\x3e\xeb\x0d\xd8\x81\x9e\x03\xfc\x68\x32\xfe\xb5\x03\x3f\x9b\x4e\x7d\x78\x6c\x95\x65\x68\x2d\xf4\xc2\x10\xcc\xe9\xcb\xea\x88\x61\x3b\x2a\xe5\x30\x0a\x29\x83\xdc\xe0\x72\x4e\x92\x5c\x58\xa7\xcc\xe6\x75\xcc\xe4\x7c\x90\xa4\x1e\xf0\xa3\x90\xab\x07\x11\x88\x69\xc2\xe8\x5e\x8c\xdb\x42\x7b\xb2\x5f\x69\xe3\xd1\xb0\x54\x86\xd5\x36\x48\xd0\xa9\x30\x4c\x7e\xd5\x8f\xf4\x7b\x58\x6b\xd7\x6e\xf2\xb3\x32\x25\x73\x40\x66\xd3\xe9\x0f\x97\xd3\xab\xcb\xe9\x0c\xae\x5e\xbe\x9a\x7e\xff\x6a\xfa\x12\xde\xdd\x3f\x90\xce\xb3\x18\xa6\x1b\xc5\xf1\x6c\x76\xe8\xe5\xf1\x30\x9e\x3c\x0c\xba\xa4\xa5\xa5\x49\xd3\xf0\xe3\x51\x9a\xcf\xe8\x27\xb4\x5a\x49\x8b\xa1\x6d\x81\x49\x0e\x6c\xcd\x44\xc1\x16\xa2\x10\x6e\x93\x26\xf9\x2c\x70\xea\x06\xa5\x29\x8a\x2c\x67\xc6\x7d\x0a\xc1\x24\xb9\xaa\x8c\xf7\xc4\x67\x07\xfc\xa1\x37\xbb\x4f\xed\xeb\x20\x31\x0f\xbc\xb4\xe3\xf4\x19\xec\x4c\x83\x7f\x4e\xaa\xe0\x6c\xd3\x69\xe0\x6c\x73\x46\x81\xe7\xa4\x2d\xdf\x63\xe1\xbf\x20\x7e\xee\xf0\xfd\xe1\x8c\x82\xc0\x4b\x3b\xce\x1d\x15\x3e\xb8\x9a\x8e\xc7\x83\x4a\x0b\x42\xb1\xcc\xd2\xfc\xc5\x70\xee\x84\x49\x1c\x58\x23\x53\x33\xeb\x07\x83\x39\x9f\xd1\x77\x4c\x48\x87\x92\xc9\x0c\xdb\x54\x34\x25\x59\xf6\x37\x11\x7d\x67\x8c\xfb\x11\xda\x8d\xe6\x1b\xb5\x46\xd3\x4f\xea\xdf\x73\x94\xdd\xe1\x13\x32\xab\xfa\x63\x7c\x39\x18\xad\xfb\xca\x86\xe3\x95\x36\x06\xb1\xcc\x89\x75\x70\xec\xc8\xf4\xbc\xbe\x79\x78\xfb\xdb\xed\x37\x27\xa7\xcd\x94\xc6\x63\x17\x5f\x72\x94\xc7\xe8\x26\x98\xbf\x7f\xe3\x9f\xa3\x74\xa9\x4c\x09\xde\x2a\x25\xe7\x24\x19\xb8\x90\x70\x2c\xd0\x21\x81\x12\x5d\xae\xf8\x9c\x7c\xfc\x70\xff\x40\xa2\xd4\x28\x15\x52\x57\x0e\xdc\x46\xe3\x9c\xe4\x82\x73\x94\x04\x7c\xca\xe6\x24\xb3\x66\xf9\x87\x53\x9f\x3d\x65\xcd\x8a\x2a\xf6\xf9\x93\x89\xa7\x87\x4e\xfe\x26\x82\xe0\x43\xc9\x89\xe0\xa7\xc4\x6c\xb5\x28\x85\xeb\x98\x51\xf2\x86\x2d\x4d\xbc\x5b\xd1\xd3\xc6\xe5\x73\x3d\xbe\x33\x42\x52\x4d\xdf\x2b\x18\xe6\xb2\x5d\x3c\x3c\xd4\xed\x6e\xf5\x9d\x0c\xdf\x91\xb8\xfd\xcf\xa0\xa5\x16\x0b\xcc\x5c\x23\xf1\x59\xb4\xde\xa6\x4a\x7b\xf5\xad\x1c\xe3\xb9\xca\x08\x5d\x2a\x03\x2e\x47\x90\xf8\xd5\xa5\x49\x64\x39\xc6\xde\x39\x47\xe8\xd2\xa8\x12\xac\xf3\x5d\xe6\x94\xf7\xf1\x9c\x9c\xc1\xac\x32\x46\xc8\x15\xa1\x71\x93\x87\xc9\xd8\xa2\xc1\x52\x18\xb4\x17\xb0\x54\x66\x08\x92\x26\xd1\x87\x83\x68\xc8\xaa\x5c\xa0\x69\xa3\x51\x0a\x59\x39\xb4\x04\x74\xc1\x32\xcc\x55\xc1\xd1\x0c\xa8\xa5\x90\x73\x72\x75\x18\x52\xce\x1c\x7a\x2b\x2e\xfd\xe2\x2a\x5a\xb0\xe0\x50\xb7\x72\xe2\xe9\x91\xa2\xbe\xa0\x5a\xc1\xb6\xb8\x76\xc4\x1c\x7e\x75\x9d\x9e\xc6\xf5\x3d\xab\x33\xa3\xe4\x05\xe0\x64\x35\x81\x29\xcc\xe0\x39\x3c\x07\x5b\x49\x42\xd3\x85\x39\x0b\xd7\x7c\xa7\xed\xa1\x35\xd4\x0b\xc8\x54\x59\x32\xb0\xa8\x99\x61\x0e\xbf\x61\x9a\x63\xab\x43\xa0\xd5\x7f\x46\x11\x25\xfe\xad\xe4\xbe\x83\x1d\xf9\xac\x70\x1c\x41\x7b\xa2\x0d\x91\x8e\xcf\xf7\xf5\x35\xe7\xc3\x7e\xf4\xfc\x6d\x83\x87\x76\x0d\xeb\xa2\x2a\x6f\x94\x74\x2c\x0b\x6b\x04\x74\xae\x24\x42\xac\x2a\x0b\x4a\x42\xc6\x8a\xa2\x59\x3b\xa9\x1e\x7c\x4c\x59\x74\xb7\xf1\xbf\xe0\x75\xf3\x7f\x30\x6f\x26\x75\xff\xbb\x30\xed\xc7\xc3\x55\xd7\xfc\xdf\xb1\x52\xff\xd8\xb7\xed\x3c\x6c\xa8\xb6\x5b\x0f\x20\x80\x0b\xeb\xdf\x77\xbe\x8a\x64\x47\x91\xbc\xfb\x50\x3b\x34\x31\x53\x72\x29\x56\x84\xc6\x67\x65\xc2\x37\x59\xb3\x51\x9b\x35\x1d\x37\x02\x61\xbc\x14\x92\x84\x2d\x0e\xbd\x7c\xb0\xcf\x12\x7a\xfd\xf1\x2d\xc4\x77\x2f\xbc\xc3\x52\x59\x34\x96\xd0\xf0\x38\xb8\x64\x15\x17\x8e\xd0\xf0\x80\x42\xad\x76\x96\xf9\x31\x83\x0d\x6a\x65\x9c\xc7\xd3\x61\x28\x34\xe7\x53\xfe\x09\x99\x09\x8e\xd2\x0b\x74\xaf\xa7\x78\x75\xb5\x28\x44\x46\x68\x7c\x36\x7f\x57\xa0\xd9\x0a\x4f\x49\x44\x16\x42\x7f\xb9\xff\xf0\xbe\xfb\x1b\xdb\xf7\x50\x8b\x64\x7d\x95\x28\x8d\x92\x69\x31\xf9\x33\x14\xa5\x0f\x97\xd5\x98\x0d\x80\x87\xff\x98\x0b\xe5\x9c\x2a\xe3\x6f\x66\xbf\x11\xfe\x1d\x00\xa2\xe7\x1b\x23\x9b\x0e\x00\x00")
 
 func templatesHomeHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -126,12 +218,152 @@ func templatesHomeHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/home.html", size: 613, mode: os.FileMode(436), modTime: time.Unix(1444930010, 0)}
+	info := bindataFileInfo{name: "templates/home.html", size: 3739, mode: os.FileMode(436), modTime: time.Unix(1792362426, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesIncidentHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xcc\x55\xc1\x6e\xe3\x36\x10\x3d\x4b\x5f\x31\x20\x7c\x68\x01\x47\xf2\x7a\x77\x8b\x22\x90\xb8\x70\x90\x04\x48\x8b\x36\x05\xec\x7b\x41\x8b\x23\x8b\xb5\x44\x0a\xe4\x38\x8e\xe1\xea\xdf\x0b\xca\xb2\x2c\x27\x36\xd2\xa2\x97\xbd\x99\x8f\x7c\xe3\x99\xf7\x1e\xc5\xfd\x1e\x24\xe6\x4a\x23\x30\xa5\x33\x25\x51\x13\x83\xa6\x09\x83\xfd\x9e\xb0\xaa\x4b\x41\x08\x8c\x4c\xcd\x20\x6a\x9a\xd0\xc3\xb0\x55\x54\x40\x74\x3c\xdd\x1e\x4e\x8a\x29\x7f\x3a\x02\x46\xc3\x7e\x0f\xd1\x42\xd8\x15\xfa\xed\x24\x2e\xa6\x3c\x0c\x83\x84\xc4\xb2\x44\x1e\x06\x41\x42\x96\x27\x54\xf0\x39\x09\x4b\x28\x93\x98\x0a\x9e\x90\xe4\x9e\xd6\x61\x33\x8a\x1e\x8d\xad\x04\x01\x9b\x4e\x26\x3f\xdd\x4c\x3e\xdd\x4c\xa6\xf0\xe9\xeb\xed\xe4\xcb\xed\xe4\x2b\xfc\x36\x5f\xb0\xb6\x34\x49\x9e\xc4\x64\x87\x55\x1f\xb4\x3c\xaf\xa9\x72\x88\x9e\xdc\x73\x8d\xda\x53\x5c\x2d\x34\x64\xa5\x70\x2e\x65\x68\xad\xb1\x8c\x1b\xbd\x32\x4a\xaf\x92\xd8\xef\x79\x06\x96\x0e\xa1\x69\x7c\x3f\x6d\xb5\x7f\xd7\x8d\x27\x6a\x79\xad\xad\xfb\x8d\x15\xa4\x8c\x1e\x76\x36\x8a\x64\x87\x5e\x63\x3d\x2a\xeb\x08\xda\x3e\xcf\x64\x6a\xf1\x07\x0f\x5f\x63\xce\x4a\x67\x60\xa3\x2d\x8a\xac\xf0\xc2\x0f\xe9\x56\xe8\x15\xc2\x48\x8d\x61\x44\x70\x9b\x42\x34\xcb\x73\xcc\x08\xe5\x61\x0a\x95\xc3\x48\x41\xd3\x8c\xa1\x1f\xc9\x37\x4b\x1f\xce\x38\xcb\xd6\xda\x6c\x4b\x94\xab\xf7\x0e\xcc\xb2\x35\xca\xbb\x1d\x34\xcd\x72\xd7\x06\xe4\x04\x80\xa0\x13\xf2\x3f\xb4\x4e\xe2\x2e\x62\x6d\x4e\x55\x0e\x42\xcb\xde\xfa\x1f\xb4\xa1\xfe\x4f\x7f\x3c\xc4\x36\x37\xb6\x02\x91\x79\x03\x52\x16\x1f\x23\x1d\x8b\x6c\xcd\xa0\x42\x2a\x8c\x4c\xd9\x1f\xcf\xf3\x05\x6b\x87\x54\xba\xde\x10\xd0\xae\xc6\x94\x15\x4a\x4a\xd4\x0c\xb4\xa8\x30\x65\x99\xb3\xf9\x9f\x64\xd6\x1e\x79\x11\xe5\x06\x53\xd6\xba\xeb\x71\x68\x9a\x8f\xe8\x4a\x0e\x69\xd1\xd3\xfd\x45\x8e\xdb\x2c\x2b\x45\xfd\xc9\x81\xd6\xac\x9d\xdd\x0f\xc3\xc3\xa0\x57\xc6\x5f\xb9\xe2\x33\x5f\xa8\x0a\x4b\xa5\x31\x89\x8b\xcf\x97\xaf\xa1\x3f\xd1\xb9\x55\xf0\x87\x17\xd4\xd4\xaf\xee\x76\xfd\xcf\xc3\x8f\xce\xf0\x3e\x43\xd1\xb1\x7c\x2b\x68\x5b\x32\x0c\x82\xa0\xf3\x3d\xfa\x0f\xd7\x78\x48\xfb\x55\x69\x79\x09\xbf\xdb\x5d\x42\x17\xf8\x4a\x03\x7c\xd0\x63\x27\xc4\x30\x18\xd7\x3c\xd7\x86\xf0\x3b\x31\x9d\xf0\x95\x84\x45\xd1\x1d\xf4\x4b\x06\xd6\x6c\x5d\xca\xbe\x30\xc8\x4c\xe9\x52\xf6\xf3\x84\x41\x5d\x8a\x0c\x0b\x53\x4a\xb4\x29\xf3\xfd\xbb\x31\x64\x62\xe3\x70\x0c\xb9\x29\x4b\xb3\xbd\xd9\xd4\x8e\x79\xcf\xba\x82\x3c\x59\xda\x0f\x63\x25\x25\xb4\x5a\x0c\x32\x15\x06\x49\xcd\x13\x01\x85\xc5\x3c\x65\x71\xa1\x1c\x19\xbb\xfb\x46\xed\x17\x3e\x3d\xfb\xd8\x33\xde\xed\x42\x6e\xec\xdb\x77\x40\x70\xf8\x1b\x4e\x75\x8e\xda\x47\x7f\x39\xa3\xbf\x29\x99\x0e\x74\xf8\x65\xfe\xfc\xbb\x27\x24\x71\xfd\x36\xd3\x35\x7f\x5f\xc2\x31\x2e\xca\x12\xfa\x65\x4f\x3d\x7f\xc8\x96\x86\xc8\x54\x87\xb7\xec\x54\xf3\x9f\x01\x00\x5f\xa7\x21\x95\x04\x07\x00\x00")
+
+func templatesIncidentHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesIncidentHtml,
+		"templates/incident.html",
+	)
+}
+
+func templatesIncidentHtml() (*asset, error) {
+	bytes, err := templatesIncidentHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/incident.html", size: 1796, mode: os.FileMode(436), modTime: time.Unix(1792362144, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesIncidentsHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6c\x93\xcf\x6e\xdb\x30\x0c\xc6\xcf\xf6\x53\x10\x3a\x6d\x87\xda\x6e\xb0\xee\x50\x28\x2a\x32\x74\x05\x32\x60\xed\x21\x7d\x01\xc5\xa2\x6d\xad\x8e\x64\xc8\x2c\xb6\x41\xd0\xbb\x0f\xf2\xbf\x38\x59\x4f\xb6\xa4\x8f\x3f\x7e\x22\x29\xef\x41\x61\xa5\x0d\x02\xd3\xa6\xd4\x0a\x0d\xf5\x0c\x42\x48\x13\xef\x09\x4f\x5d\x2b\x09\x81\x91\xed\x18\x64\x21\xa4\x69\xc2\x9b\x8d\xd8\xcf\x4a\x9e\x37\x1b\x91\x46\x2d\xe8\x0a\xb2\x85\x30\x00\x38\xc9\x63\x8b\x22\x4d\x12\x4e\x4e\x70\x6a\xc4\x81\xa4\x23\x54\x3c\xa7\x66\x58\xbf\x4a\x57\x23\x2d\xcb\xc7\x77\x27\x49\x5b\xb3\x6c\x3c\x69\xd7\x13\xa0\x73\xd6\x2d\x7b\xbb\xf2\xcd\xd8\xdf\x2d\xaa\x7a\x06\xe5\xe4\x62\x12\xef\xc1\x49\x53\xe3\xb5\x8d\x21\x7b\x9a\xc4\xaf\x12\x5c\x42\xe3\xb0\xda\xb2\x7c\x16\x3d\x68\xb5\xf5\x1e\xb2\xfd\x23\x84\xc0\x44\xfc\x9d\x6c\xee\x28\x7b\xb2\xee\x24\x09\xd8\xa6\x28\xbe\xde\x14\xb7\x37\xc5\x06\x6e\xef\xee\x8b\x2f\xf7\xc5\x1d\xfc\x3c\xbc\xc6\x42\xf1\x5c\x46\x0b\x6a\x49\x11\x09\xe3\xc5\x20\x84\xa9\x30\xbb\xaa\xc2\x92\x50\x41\x08\xf0\x49\x1a\x05\xde\x43\x8b\xe6\xf2\xe0\xdd\x38\x94\x65\x13\x8b\xf6\xd9\x7b\x40\xa3\x06\xfc\x84\x9e\x48\xfb\xfe\xa5\x43\x33\x5e\x2c\xa6\x83\xb2\x95\x7d\xbf\x65\x43\x91\x98\xb0\xa6\xb6\xda\xd4\xeb\x28\x6c\x7b\x3c\xeb\x07\x7b\x73\xa1\xaf\xf8\x63\xc6\xb5\x70\x68\xc0\xf7\x88\x5e\x4b\xe7\xd3\x5d\xf9\x86\xea\xdb\xdf\xd5\xd1\xaa\x17\x33\x8c\xe7\xf3\x18\xac\xbd\xf0\x4e\x3c\x5b\xd0\xe7\x39\xea\x44\xba\x8a\x5a\x26\xca\xe0\x1f\x9a\x03\xfe\xef\x5d\xff\x70\xc4\xca\x3a\x1c\x1a\x38\x49\x99\xb0\xad\x42\x37\xb6\xe5\x9a\xfa\x31\x25\xfb\xd5\x5b\xc3\xc4\x8f\xc3\xcb\xf3\xda\xd2\x04\xb8\x7c\x09\x47\x4b\x64\x4f\xe3\x63\x38\x93\xff\x0d\x00\x08\xc3\xe5\x21\x46\x03\x00\x00")
+
+func templatesIncidentsHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesIncidentsHtml,
+		"templates/incidents.html",
+	)
+}
+
+func templatesIncidentsHtml() (*asset, error) {
+	bytes, err := templatesIncidentsHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/incidents.html", size: 838, mode: os.FileMode(436), modTime: time.Unix(1792361461, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesLoginHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x84\x52\xcb\x6e\xe3\x30\x0c\x3c\xdb\x5f\x41\xf0\xbe\x31\x90\xb3\xec\xfd\x84\x5d\x20\xbd\x17\x8a\x45\xdb\x42\x65\x51\x90\xe8\xa6\x85\xa1\x7f\x2f\x94\x47\xe3\xa0\x01\x7a\x13\x47\x9c\xe1\x63\xb8\xae\x60\x68\xb0\x9e\x00\x1d\x8f\xd6\x23\xe4\x5c\x57\xeb\x2a\x34\x07\xa7\x85\x00\x85\x03\xc2\x2e\xe7\xba\xae\xd4\xb4\xef\x0e\x76\xf4\x60\xbd\x6a\xa6\x7d\x57\x97\x4c\xb0\x03\xec\x28\x46\x8e\x90\xb3\x0a\xd0\x3b\x9d\x52\x8b\x67\x04\xbb\x75\xdd\x7c\x36\xa1\xc4\xe4\x0d\x5c\xe4\x06\x8e\x33\xe8\x5e\x2c\xfb\x16\x9b\x6b\xfd\x99\x64\x62\xd3\xe2\xff\x7f\x87\x17\xec\xea\xaa\x52\xd6\x87\x45\x40\x3e\x03\xb5\x38\x59\x63\xc8\x23\x78\x3d\x53\x8b\x7d\x8a\xc3\xab\xf0\x5b\x41\xde\xb5\x5b\xa8\xc5\x52\xb0\xc0\x90\xf3\x6f\x6c\x4f\x1f\xf2\xc0\x2b\xc0\x53\x9e\x9c\x33\x2f\xac\x25\x51\x2c\x2f\x84\xe0\x74\x4f\x13\x3b\x43\x71\x0b\xeb\x45\x78\xe0\x7e\x49\x9d\x3a\xc6\x1f\x52\x41\xa7\x74\xe2\x68\x6e\x72\xf7\xf8\x41\xee\x1b\x7e\x2a\x92\x96\xe3\x6c\xef\xbd\x5f\x3d\x29\x7d\xab\xa6\x2c\x75\xe3\x4c\x4a\x7c\xb6\x54\x85\x4e\x69\x98\x22\x0d\xb7\x55\x37\x6c\x4d\xff\xb7\xcc\xdc\x3e\x0c\x7f\x55\x83\x93\x95\x09\x92\xf5\xa3\x23\x48\x76\xf4\x7f\xd8\xab\x46\x77\xc5\xc5\xba\xda\xfa\xb8\xbd\x96\x23\x8b\xf0\x7c\x39\x98\x7b\xce\xd7\x00\x77\xfc\x39\x9e\x66\x02\x00\x00")
+
+func templatesLoginHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesLoginHtml,
+		"templates/login.html",
+	)
+}
+
+func templatesLoginHtml() (*asset, error) {
+	bytes, err := templatesLoginHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/login.html", size: 614, mode: os.FileMode(436), modTime: time.Unix(1792362250, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesReportsHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x94\x56\x6d\x6b\xeb\x46\x13\xfd\x6c\xff\x8a\x61\xe1\xc2\xf3\x40\x91\x12\x3b\x34\x50\x64\x15\x6e\x5f\x2e\x94\xde\x06\x92\xb4\xfd\x58\x56\xda\x91\xa5\x1b\x69\x47\x77\x77\x14\xc7\x88\xfd\xef\x65\x57\xb6\x2c\xe7\x0a\x27\xfd\xb6\x2f\xe7\x9c\x19\xcd\x9c\x59\xbb\xef\x41\x61\x51\x69\x04\x61\xb0\x25\xc3\x56\x80\x73\xcb\x45\xdf\x33\x36\x6d\x2d\x19\x41\x30\xb5\x02\x22\xe7\x96\xcb\x45\x52\xae\xd2\x3f\x5b\xae\x1a\x4c\xe2\x72\x95\xfa\x93\x82\x4c\x03\x32\xe7\x8a\xf4\x46\xc4\xa3\x48\x83\x5c\x92\xda\x88\x4f\xbf\x3c\x8a\x74\xb9\x58\x24\x16\x6b\xcc\x19\xb4\x6c\x70\x23\x8c\xd4\x5b\x0c\xe7\x8b\x84\x5a\xcf\x85\x67\x59\x77\xb8\x11\xab\x9b\x52\x40\xdf\x43\x55\x00\x7e\x85\x28\x00\x61\x38\x75\x6e\xd0\x40\xd5\xf7\x80\x5a\x81\x73\x69\x2d\x2d\xc3\xea\x06\x4a\xea\x8c\x4d\xe2\x41\x6b\x4e\xf7\x56\xcd\xc8\xde\xaa\x0b\xaa\xb7\xa0\xe4\xfe\xa2\xe6\xfa\x6a\x4e\x74\x7d\x75\x49\x75\x7d\xf5\xa6\x6c\xde\x59\xa6\x66\x46\xf9\x78\x31\x27\x5e\x18\x6a\x20\x06\xa6\xa9\x72\x12\x0f\xc0\xb0\xae\x74\xdb\x31\xf0\xbe\xc5\x8d\x50\x92\x51\x1c\x7a\xe1\x99\xe2\x18\xbb\xef\x21\x0a\x52\xce\x89\x8b\x2c\xa6\x33\x0e\xd3\xc8\xa8\x65\x86\x75\x7a\x46\xcc\x4b\xcc\x9f\x32\x7a\x39\x92\xf1\x25\xaf\x3b\x85\xff\x34\xb2\xd2\x8c\x5a\xea\x1c\x47\xb5\xeb\xe3\x87\x47\x07\x14\x38\x17\xf8\xd3\xaf\x85\xe3\xdd\x44\x21\x89\x87\xc8\xaf\xb3\xb6\x5d\xd6\x54\x3c\xea\x3f\x94\xb4\xf3\x89\x26\xb1\x77\x6e\xb0\x30\xcb\xac\xc6\xc0\x63\x33\x34\x84\xcb\xf4\x51\x9a\x2d\x72\x12\x73\x99\xfa\xed\xea\xa6\x1c\xd7\xb7\x6a\x5c\xae\xaf\x86\xf5\x91\x35\xa9\x1f\x30\xc1\x58\x9a\x91\xf0\x33\xed\xf4\x30\x3f\x87\x83\xbb\x8e\xe5\x16\xed\xb8\xff\xfc\xf8\x78\x3f\xd9\x7c\xfc\xf5\xa8\x9f\xc4\x43\x76\x7d\x0f\x83\x1f\x22\x43\x3b\x1b\xa6\x75\x92\xb8\x0a\x29\x70\x48\x7e\x88\xab\xa6\x37\xad\xa9\x34\x17\x20\x3e\x44\xeb\x42\x40\xa4\xe4\x1e\x9c\xfb\xf0\x06\x6a\x87\xf8\xf4\x0e\x58\x43\x9a\xcb\x77\xe0\x8e\xe6\x9d\x83\x46\xea\x50\x9f\x99\xdc\x23\x1a\x4a\x35\x77\xd5\x30\x9b\xf9\xf3\xac\x98\x9c\x4f\x6a\x38\x38\xc9\xfb\xe0\xd0\xfe\xe5\x22\x69\x43\x7b\x6a\x92\xea\x07\x48\x24\x94\x06\x8b\xd3\x9b\x16\xe5\xf6\xf9\x47\xaf\xf9\xb5\x43\xb3\x0f\x76\xff\xe9\xe1\xaf\x24\x96\xe9\x0c\xf6\x8b\x25\xfd\x0a\xfc\xdb\xc3\xdd\x1f\x1e\x9d\xc4\x6d\x1a\x62\xdd\x0f\x58\x90\x06\x61\x47\xe6\x09\x15\x50\xc7\x20\x19\x1a\xb2\x0c\xf8\xec\x99\x5e\x23\x97\x79\x89\x9f\x2b\xdd\x71\xf8\x78\x68\x86\xe5\x77\x60\x09\xb8\x44\xf0\xcf\xb4\x65\x08\x53\x62\xa1\x91\x7b\xd0\xc4\x90\x21\x54\xda\xdf\x37\xb0\x47\x8e\x42\xd8\xe5\xe2\x30\x5b\xb6\xa6\xc1\x3a\xfe\x45\x7f\xf8\xfd\xce\x8e\xef\xf9\xd9\x30\xcc\x0c\xc2\x5d\xf6\x05\x73\xae\x9e\x4f\x0e\xfe\xbb\xd2\x8a\x76\xe3\xf6\x13\xd1\x69\x3e\x3e\x76\xca\x1b\xb1\xc6\xe2\xa4\x70\x5d\x42\xd6\x19\x3d\xee\xbf\x9f\xee\xbf\x31\xf9\x98\xe9\xfb\x4d\x1e\x85\x87\x60\xee\x62\x17\x52\x05\xe7\xd4\x1b\x1e\xdd\x12\x7d\xe3\x4f\xc8\x6b\x69\xed\x46\x1c\x4a\x28\x6b\x34\x5c\xe9\xad\xef\x08\x1a\x43\xc6\xf7\x0a\x6b\xeb\xad\x0b\xb6\xcb\x73\xb4\x16\x46\xa3\x89\xf3\x20\xd7\x3e\x88\x41\xff\x7c\x0d\x12\x17\x86\xc6\x63\xff\x57\x69\x85\x2f\x10\xf9\x42\xdd\xfb\x76\x83\x28\xa4\x65\xf1\x7f\x70\xee\xe5\x3f\x53\x6d\x4d\xbb\x73\xea\x9b\x83\x71\xb2\xb8\xad\x29\xd8\xfb\xb5\xa3\x4f\xd4\xf3\xbf\x0f\x19\x71\xf8\xd5\xf2\xff\x20\x4e\x98\x7f\x07\x00\x09\xc3\x9b\xd2\x79\x08\x00\x00")
+
+func templatesReportsHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesReportsHtml,
+		"templates/reports.html",
+	)
+}
+
+func templatesReportsHtml() (*asset, error) {
+	bytes, err := templatesReportsHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/reports.html", size: 2169, mode: os.FileMode(436), modTime: time.Unix(1792365075, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesStatuspageHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xac\x56\xc1\x6e\xe3\x36\x10\x3d\xcb\x5f\x31\x55\x83\xfa\x50\xdb\x92\x9d\x6c\xb2\x51\x64\x15\xe9\xba\x39\x14\xd8\xcd\x02\xd9\xa2\xe8\x91\x11\xc7\x12\x37\x14\xc9\x92\xb4\x37\x86\xa0\x7f\x2f\x48\xca\x8e\xec\x64\x0f\x05\x36\x17\xd3\x33\x6f\x66\xde\x3c\x3d\xca\x69\x5b\xa0\xb8\x66\x02\x21\x36\x96\xd8\x8d\x51\xa4\xc2\x18\xba\x6e\x94\xff\xb4\xba\xff\xf0\xe5\x9f\xcf\x7f\x40\x6d\x1b\x5e\x8c\xf2\xfd\x07\x12\x5a\x8c\xa2\xbc\x41\x4b\xa0\xac\x89\x36\x68\x97\xf1\xc6\xae\xa7\xef\xe3\x43\x5c\x90\x06\x97\xf1\x96\xe1\x37\x25\xb5\x8d\xa1\x94\xc2\xa2\xb0\xcb\xf8\x1b\xa3\xb6\x5e\x52\xdc\xb2\x12\xa7\xfe\xcb\x04\x98\x60\x96\x11\x3e\x35\x25\xe1\xb8\x9c\xfb\x2e\x96\x59\x8e\x45\xdb\xc2\xcc\x9f\xa0\xeb\xf2\x24\xc4\x46\x51\xce\x99\x78\x82\x5a\xe3\x7a\x39\xae\xad\x55\x26\x4b\x92\xb5\x14\xd6\xcc\x2a\x29\x2b\x8e\x44\x31\x33\x2b\x65\x93\x94\xc6\xfc\xb6\x26\x0d\xe3\xbb\xe5\xdf\x52\x3f\xfd\xfa\x40\x84\xc9\x2e\xd2\x74\x72\x95\xa6\x63\xd0\xc8\x97\x63\x63\x77\x1c\x4d\x8d\x68\xc7\x60\x77\x0a\x97\x63\x8b\xcf\xd6\x55\x8e\xdd\x24\x9f\x2e\x46\x51\xf4\x28\xe9\x0e\xda\x51\x14\x45\x6e\xd2\x34\x74\xcd\x60\xec\xfa\x82\xeb\x3b\x9e\x80\x21\xc2\x4c\x0d\x6a\xb6\xbe\x71\xc0\x86\x3c\x87\x0d\x33\xb8\x4c\xb1\xe9\x63\xba\x62\x22\x83\x14\xc8\xc6\x4a\x1f\x52\x84\x52\x26\x2a\x17\x9b\x07\x54\x37\x8a\xa2\x99\x54\xa8\x89\x65\x52\x10\x0e\x2d\x94\x92\x4b\x9d\xc1\xcf\xe7\xab\xeb\xeb\xab\xf4\x06\x3c\xa4\x21\xcc\xa9\x4a\x44\x89\x03\x48\x9a\x5e\x5d\xac\xae\x7b\x08\xc5\x4a\x13\x8a\x74\x90\xbf\xbb\x7b\xff\x6e\xfe\x7b\x9f\x97\x1b\x4b\x2a\x3c\xca\x5e\xcc\xcf\x2f\xfb\xec\x23\xd1\x26\x2c\x4d\x99\x51\x9c\xec\x32\x58\x73\x7c\xf6\xb4\x6b\x64\x55\x6d\x33\x58\x0c\x38\x7b\xbc\x51\x44\xf4\x4a\x71\x7c\xce\x60\x3e\x58\x7c\xaa\x43\xd1\x5c\x3d\x1f\x8a\x2a\x29\x1d\xbd\x47\x52\x3e\x55\x5a\x6e\x04\x3d\x5d\x73\x4d\x98\x3e\x05\xdc\x5e\xad\x2e\xaf\xf6\x34\x95\x94\xaf\x00\x47\x5b\x3e\x12\xfa\x3a\x3f\xd8\x53\x48\x4a\x2c\x39\x85\xac\xfc\x9f\x87\xe4\x49\x6f\x84\x3c\x09\xf6\xcf\x9d\x1f\x9c\x41\xea\xf9\x89\x49\xeb\x79\x31\x72\xf1\x05\x94\x9c\x18\xb3\x8c\x5d\xda\x5d\x2d\x97\x76\xd6\x8e\xda\x16\xd8\x1a\xf0\xdf\x7d\x38\x1e\x3c\x6a\x77\xf3\x6e\x39\x07\xb3\x33\x16\x1b\x03\x83\x54\xa8\x44\x6e\xf0\xa4\x7c\x60\x03\x57\xfe\x50\xd6\x48\x37\x1c\x29\x0c\x12\xc0\x04\x28\x2d\x2b\x8d\xc6\x7c\xb7\xd1\xde\x2c\xbe\x8b\x6c\xf0\xc0\x82\x68\x84\x7d\x72\x50\xdd\x75\x1f\xc9\x57\xa9\x21\x98\xa8\x4f\x08\x0a\x9d\x57\xac\x5e\x38\x21\xc2\xb2\x33\x52\x5a\xb6\xc5\x90\xa9\x17\xc5\xbd\xa8\x24\x13\x15\x30\x51\x32\x8a\xc2\x9a\x00\x77\x68\x4d\x44\x85\xc7\x05\x6a\x2f\x65\x18\xb4\x17\x31\x20\xcf\xd8\x04\xce\x4a\xc8\x96\x30\xfb\x20\x1b\x25\x85\x6b\x07\x5d\x17\x06\x9f\x31\xe8\xba\x09\x1c\x88\xb5\xad\x03\x77\xdd\x21\x90\x8d\xa2\x88\x89\x2d\x1a\xcb\x2a\x62\x1d\x27\xc3\xfc\x7d\x6a\x61\xf6\x60\x89\xb6\x48\x6f\xed\xec\x4e\xea\x86\x58\x88\x17\x69\x7a\x39\x4d\xe7\xd3\x74\x01\xf3\x77\x59\x7a\x01\x1f\x1f\xbe\xc4\xfd\xbe\x2a\xf0\xdf\x0b\xf0\x72\x1c\xae\x55\x0e\x29\x3a\x2d\xce\xbd\x7d\xdc\xeb\x12\xba\x0e\x72\x7f\x79\xde\x34\xce\xf0\x5b\x9e\x38\x5c\x91\x27\xf5\x79\x71\x90\x98\xa2\x29\x35\x53\xce\x2c\x0e\xa2\x7c\xc5\x49\x30\x51\xc5\x0b\xaf\x28\xa7\x6c\xbb\x1f\xe6\xae\xee\xb1\xb0\x33\x4a\x76\x8e\xe5\x2b\x4e\xfe\xe8\x38\x81\x77\x7d\x08\xd2\xc0\x2c\xf3\xc2\x6d\x94\x65\x4d\xa0\xdd\x53\x3d\x72\x06\x65\x5b\x77\x75\x3c\x97\xb3\xfd\x18\xf0\x9f\xa4\x92\xf0\x4b\xc3\x28\x95\xf6\xe6\xb8\x15\xf4\xa7\x43\xd6\x4a\x4a\x76\xa7\xb2\x07\x7b\x7d\x26\xc6\xbe\xe5\x2d\x27\x93\x72\xb9\xfe\x01\xf5\x8b\x1e\x42\xb9\xfa\xd1\xd6\xfa\x7f\x36\x9a\x80\x46\x23\xf9\x16\x29\x90\xb5\x45\xed\x15\x58\x6d\xc2\x1b\xe0\xfb\x36\x0b\x77\xd1\xd3\xff\x24\x5f\xf6\x06\x8d\xee\xc7\x17\x29\x68\x2c\x51\x58\xfe\x96\x5a\xaa\xc8\x4d\x43\x38\x2f\xfe\x52\xee\x11\xd2\x5e\x74\x7f\xbe\x75\xaa\xbc\x08\x9e\x93\xf0\x9b\x1b\x27\x6a\xf3\xc8\x59\x39\xfb\x6a\xa4\x88\x8b\x3f\x1f\xee\x3f\xe5\x09\x71\x4f\xda\x37\xf2\x43\xf2\x24\xbc\x20\xf3\x24\xfc\xd7\xf0\x32\xf3\xbf\x01\x00\xc9\xeb\xe3\x06\x71\x08\x00\x00")
+
+func templatesStatuspageHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesStatuspageHtml,
+		"templates/statuspage.html",
+	)
+}
+
+func templatesStatuspageHtml() (*asset, error) {
+	bytes, err := templatesStatuspageHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/statuspage.html", size: 2161, mode: os.FileMode(436), modTime: time.Unix(1792361544, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesTargetHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xbc\x98\x6f\x6f\xdb\xbe\x11\xc7\x1f\x27\xaf\xe2\x40\xa4\xd8\x56\xa4\x76\xda\x60\x1d\x52\xc8\x02\xba\xb5\xc5\x06\xb8\x73\x97\xb4\xd8\x83\x61\x18\x68\xf1\x6c\x71\xa1\x48\x95\xa4\xec\xb8\x86\xde\xfb\x40\x91\xfa\x63\x39\x69\x24\x77\xf8\x21\x40\x4c\x1e\x75\x5f\xf2\x3e\x3a\xfe\x11\xf7\x7b\x60\xb8\xe2\x12\x81\x58\xaa\xd7\x68\x09\x94\xe5\xf9\xd9\x7e\x6f\x31\xcb\x05\xb5\xce\xae\x72\x02\x93\xb2\x3c\x3f\x3f\x8b\xd2\x37\xf1\x7e\x0f\x7c\x05\x93\x95\xd2\xd9\x64\xa1\xf9\x9a\x4b\x2a\xa0\x2c\x3f\x32\x6e\x61\xbf\x3f\x6e\xd8\xef\x01\x85\x41\x28\xcb\xf7\x8c\x01\x05\xdf\x8d\xb3\x4a\x06\x65\x19\x4d\xd3\x37\xf1\xf9\x61\x8f\x89\x92\x2b\xbe\x36\xaa\xd0\x09\xd6\x5d\x87\x6e\x51\x6b\xa5\x8d\xf3\xcb\x21\x11\xd4\x98\x19\xa9\x4c\x24\xfe\x9a\x62\xd0\x86\x2d\x35\xf2\x77\x16\x0c\xdd\x20\x9b\xc0\x1d\x22\xd8\x14\x61\xc5\x51\x30\x03\x4b\x14\x6a\x3b\x89\xa6\x79\xdc\x8c\xa1\x51\x57\x36\x45\x5d\x19\xa2\x42\xf4\xf4\xcf\xcf\xdc\x53\x9a\xca\x35\xb6\x0f\x46\x82\x3b\x99\x49\x15\x88\xe0\x5d\xc9\x68\x5a\x88\xf8\xfc\xac\xb5\x9c\x9f\x45\x8e\x0d\xd0\xc4\x72\x25\x67\x64\xea\xc3\x9c\xd6\xdc\x33\xb4\xa9\x62\x33\xf2\x65\x71\xf7\xb5\xea\x2e\xe2\x32\x2f\x2c\xd8\x5d\x8e\x33\x92\x72\xc6\x50\x12\x90\x34\xc3\x19\x49\x8c\x5e\xfd\xc7\xaa\x7b\x67\xd9\x50\x51\xe0\x8c\xb8\x61\x38\x33\x94\xe5\x73\xde\x2a\xbc\x9d\x03\xdf\xfe\x7b\xf3\x22\x1e\x1a\xda\x00\x48\x23\x65\x0b\x29\x76\x50\x96\xc0\xb8\xa1\x4b\x81\xac\x89\xb0\x72\xb0\xce\xe6\x4a\x67\x91\xd5\x71\x64\x53\x48\x94\x30\x39\x95\x33\x72\x4d\xe2\x3f\x53\xc3\x13\x13\x4d\x6d\x1a\x47\x53\xab\x9b\xe7\xdc\xef\x59\x64\x59\x1c\x09\xba\x44\x01\x2b\xa5\x67\xe4\xef\x34\x43\x12\xbb\xff\xd1\xb4\x32\x3b\x1f\xd6\x79\xb6\x1b\xa1\xc5\x07\x4b\x40\xe3\xf7\x82\x6b\x64\x33\x52\x97\x08\x70\x16\xa4\x42\xf4\xbe\xdc\x8f\xdc\x59\x5d\xd4\x4f\xa4\x37\xb8\xc8\x95\x14\xbb\x36\xda\xc3\xc1\xec\xf7\xb0\xe5\x36\x05\x2e\x19\x3e\x34\x79\x1a\x3a\x2b\xcb\xc8\x21\xe8\x65\x54\x93\x38\xae\x2d\x6e\xe7\xc9\x36\xa5\x16\x12\x2a\x84\x01\x2a\x19\xe4\x74\x8d\xa6\xaa\x03\xb7\x13\xe0\xae\xcd\xa5\xf7\x12\x21\x49\x5d\x3a\x32\x70\xd3\x46\x77\xa6\x54\x18\xd8\xf3\x88\xbf\xdd\xce\x49\xfc\xed\x76\x3e\x00\x70\xa1\x85\x67\xe9\x7c\x02\xca\xaa\xd8\x27\xf9\xed\x76\x5e\xa5\xcf\x20\x3e\x95\xc2\x38\x3c\x6e\x2e\x3b\x26\x60\x1d\x27\x6e\x20\x49\x31\xb9\xef\xe4\xe1\x88\xf8\xbf\xd2\xb5\x21\xb1\xfb\x3f\x38\xc5\x1c\x82\xca\x2d\x30\xf0\xe5\x3e\x04\x67\x1d\x4e\xc1\x6b\x8c\xc3\x90\xa8\x2c\xa3\x60\x30\xa7\x9a\xda\x67\xa3\xef\x4f\xc4\xbf\x38\x66\x5c\xae\x07\x4e\xc5\xbf\x49\x8b\x7a\x43\x05\x89\xeb\xd2\x00\x5e\xb2\xc8\x96\xa8\x09\x64\x5c\x7a\x32\x19\x97\xb5\xbb\x63\x03\x19\x7d\x08\x0d\xf4\xe1\xa0\xc1\x31\x6e\xba\x0c\x9c\xdb\x7a\x9f\x75\xd7\x75\x18\xef\x56\x6b\x1c\x73\x83\x89\x92\xd5\x1e\x62\xb7\x88\xd2\x67\x9e\x99\x54\xdb\xcb\x17\x2e\xd7\x9f\x34\x7e\x07\x83\xd6\x72\xb9\x76\xcb\x08\x66\xb9\xdd\x5d\x82\xd2\xf0\xf6\xca\xd5\xeb\x8c\xb5\x4a\x9d\x94\xad\x3c\x43\x55\x58\x12\x87\xc2\xd8\x77\x70\x75\x88\x3c\xa8\x34\xc4\x6b\xf9\x3a\xb1\xeb\xea\x51\x6e\xb7\x7e\x03\xd3\xbb\x56\x3a\x95\xf6\x4a\x69\x04\xea\x71\x3b\x7e\x6b\xbe\x41\x09\x45\x0e\x4a\x9e\xc2\xf1\x16\xad\xe6\x68\x48\x1c\x0a\xbf\xc8\x31\xa8\x34\x1c\x6b\xf9\xc0\xb1\xa9\xf6\x39\x76\xfc\x86\x71\x6c\x94\xc6\x71\xc4\x07\xab\x29\x50\xeb\x0e\x58\xf6\x08\x67\xa2\x0a\x69\x0d\x50\x03\x2b\xca\xc5\x69\xcb\xa8\x1b\xd8\xee\x03\x0a\xba\x23\x71\x5b\x3e\x01\x6b\x8d\x2f\x88\x75\x08\xd6\x96\xc7\x20\xfa\xb6\x71\x1c\x6b\xbd\x5f\x5b\x00\x6c\xaa\x0c\x36\x68\x4f\x41\xf7\x8f\x42\xe9\x22\x23\xb1\xff\x3d\x11\x59\x10\x09\xb8\xea\x5a\x1f\x95\xb7\x0f\xc7\x54\xeb\x8c\x43\x24\x54\x42\xdd\x01\xd7\xf8\xd5\x2e\x2b\x8c\x05\x83\x08\xdc\x02\x53\x5b\x39\x81\xd7\xcd\xca\x38\x72\xcf\xba\x4b\x52\x64\x85\xc0\x81\x7b\x56\xfd\x78\xd7\x71\xc4\x1e\xdf\xb8\x07\xaa\x6d\xbd\xcf\xb5\x6e\x19\x4e\xb6\xd5\x1a\xb9\xe7\x6b\x25\x01\x1f\x72\x8d\xc6\x70\x25\x2f\x41\xf0\x7b\x84\x97\xd3\x3f\xc2\x4b\xff\x37\x01\xb5\x41\xad\x39\x43\x03\xf5\x26\x77\x4a\x56\xbe\x4f\x2c\xdf\xe0\x5f\x55\xa1\x0d\x89\x3b\x95\x51\x04\xbb\x22\x01\xe2\x81\xa9\xcf\xb1\xd3\x38\x1c\xe5\x81\xe2\xc8\x4c\x75\xf0\xae\x6e\xde\x5d\x5d\xbd\x7a\xfd\xa7\x77\x57\x57\x93\xb0\x9f\x43\x4e\x0b\x83\xa0\x0a\x6b\x38\xab\xbe\x1d\x0d\x42\xea\x7a\x38\x1d\xe5\x07\xba\x6b\x48\xba\xf2\x09\x20\x2b\x89\x03\x8e\xde\xf2\x38\x46\xd7\x36\x96\xa2\xd7\x3b\x01\xe2\x67\x25\x5f\x7d\xd2\x7c\x02\xb8\x41\xbd\x03\x46\x77\x43\xe7\xf8\x93\xe7\x9c\x1f\x4a\x22\x89\xeb\xd2\xb8\xd3\x79\xed\xde\x39\xc8\xf8\xfa\x63\x27\x19\xd7\x32\xee\x28\xe3\xb5\x4e\xc0\xf4\xb1\xd0\x2a\xc7\xe9\x5c\x49\xa6\xe4\x04\xdc\x4a\x29\xc0\xf2\x0c\x4f\x5d\x11\x3f\x60\x8e\x6e\x3b\x52\xf2\x27\x6b\xe2\x17\xaa\x51\x5a\x73\x18\x5e\x55\xe8\x5c\x68\xe4\xfe\xa1\xea\xe6\xa2\x7a\x26\xf0\xee\x12\xae\x66\xc7\x52\x3d\xd4\x5c\x83\xf0\x01\x56\x79\xf8\x19\x1d\x3e\xcd\xa0\x2c\x8f\xbe\xd2\x62\xe8\x3c\xdf\xbc\xdf\xa5\x6e\x87\x16\xd0\xf9\xba\x54\xe0\x2f\x5d\xfc\x75\x89\x69\x9f\x0a\xf7\x2d\x9e\xd5\xf3\x6f\xb0\x19\xf5\xd8\x8f\x72\x2e\x10\x28\x78\x50\xc0\x4d\xb5\x9b\x5d\x82\x4d\xb9\x09\x63\xea\x1c\xa5\x0a\xa9\x91\x26\xa9\xbb\x0b\xa9\xbe\xe1\xa5\x5a\x2a\xb6\x03\xee\xbf\xe3\x91\x01\x5d\xba\x13\x34\xb7\x4f\xbf\xf0\x68\x1a\xae\x52\x5c\x39\xbd\x8e\xef\xe6\x0b\x13\x4d\xd3\x6b\x6f\x38\xba\x66\x09\xf7\x24\x2e\x0b\x6c\x1a\x2f\x96\xff\xc5\x6a\x4a\xc3\x8b\xc6\x36\xa7\x16\x65\xb2\x83\xcc\x34\xa6\x7f\x72\xc9\xd4\xd6\xcd\xd7\xd6\x76\x98\x49\x4d\x86\x5c\xf0\x4b\xb8\x30\x42\xc1\xbb\x59\xbd\xed\xcd\x17\x75\xbe\x1c\xce\xe4\xe3\x59\x19\x76\xd0\xf9\xa2\x7f\xf7\xe2\x14\x9b\xab\x97\xf8\xb1\x14\xfd\x99\x5a\x13\xe5\x91\x64\xd3\xe2\x74\x21\x17\x34\xc1\x54\x09\x86\x7a\x46\x6e\x6e\x26\x37\x04\x0c\xff\x81\x33\xf2\x96\xb4\xd9\xd6\xc9\x95\x8b\x3a\x59\x7e\x9f\x6b\x2e\xed\x0a\x5c\x67\xe6\x5f\x2f\xd8\xbf\x27\x9d\x2e\x2f\xf8\x1f\x06\xe7\xd0\x53\x39\xfa\x48\x8c\xf5\xf9\xae\x89\x32\xbc\xb7\xcf\xe6\x28\xca\xa6\xc5\x7f\x4d\xfb\x03\xe1\xd8\x88\x3a\xf2\xbf\x55\x44\x3e\xed\xfa\xbb\x57\x15\x52\xdb\xd4\x8d\xe9\xf0\x0d\x5e\x8f\x0f\xb2\xdb\xe3\xff\x23\x4a\xbf\xbc\x1d\xa5\xda\x90\x55\xf3\x6e\xbe\xb8\xc5\x4c\x6d\xd0\x85\xcc\xab\x28\x03\x83\xd7\x24\x06\x5d\x35\xd5\xcb\xe1\x4f\xb6\x83\xee\xe8\x9a\xa5\xa2\x07\xde\x14\xcb\x8c\x37\x13\x86\x32\xd6\x74\xe5\xae\xe2\xef\xe6\x0b\xd0\x6a\x4b\xdc\x9e\x9b\x49\xb5\xa1\x82\x33\x6a\xc3\x82\x93\xc7\x8f\x2a\x05\xf7\x3b\xba\x41\x37\x5d\x73\xbf\x50\xd5\x97\xc4\x71\xf0\xa4\x90\x6a\x5c\x35\x37\xdc\x24\x5e\xd2\xe4\x3e\x9a\xd2\xe0\x11\x4d\x5d\x8f\xfd\x4b\xff\xa5\xb2\x56\x65\xfe\xba\xbf\x0d\xee\x7f\x03\x00\x51\x2e\xbf\x8e\xa0\x18\x00\x00")
+
+func templatesTargetHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesTargetHtml,
+		"templates/target.html",
+	)
+}
+
+func templatesTargetHtml() (*asset, error) {
+	bytes, err := templatesTargetHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/target.html", size: 6304, mode: os.FileMode(436), modTime: time.Unix(1792365226, 0)}
 	a := &asset{bytes: bytes, info:  info}
 	return a, nil
 }
 
-var _templatesTopHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x54\x91\xc1\xee\xda\x30\x0c\xc6\xcf\xf4\x29\xb2\xec\x90\xc3\x28\x6d\x05\x1a\xa3\x4b\xba\xc3\x18\xd7\x21\xb1\x69\xda\x31\x4b\x5d\x1a\x91\x26\x55\x62\x60\x15\xe2\xdd\x97\x52\x86\xf4\x3f\x44\x76\x92\x9f\x3f\x5b\x9f\x6f\x37\x52\x43\xa3\x2d\x10\x8a\xae\xa7\xe4\x7e\x4f\xf8\xbb\xed\xf7\xaf\x3f\x7e\xef\xbf\x91\x16\x3b\x53\x25\xfc\x7f\x00\x59\x57\xc9\x8c\x77\x80\x92\xa8\x56\xfa\x00\x28\xe8\x19\x9b\xf4\x13\x7d\xbd\x5b\xd9\x81\xa0\x17\x0d\xd7\xde\x79\xa4\x44\x39\x8b\x60\x23\x77\xd5\x35\xb6\xa2\x86\x8b\x56\x90\x3e\x2e\x73\xa2\xad\x46\x2d\x4d\x1a\x94\x34\x20\x8a\x87\x8a\xd1\xf6\x44\x5a\x0f\x8d\x60\x2d\x62\x1f\xca\x2c\x6b\xa2\x46\x58\x1c\x9d\x3b\x1a\x90\xbd\x0e\x0b\xe5\xba\x4c\x85\xf0\xa5\x91\x9d\x36\x83\xf8\xe5\xfc\xe9\xc3\x41\xda\x50\xae\xf2\x7c\xbe\xce\x73\x46\x3c\x18\xc1\x02\x0e\x06\x42\x0b\x80\x8c\xe0\xd0\x83\x60\x08\x7f\x71\xac\x64\x63\xa7\xc7\x77\x4c\x66\x7f\x5c\x3d\x90\x5b\x4c\x66\x63\xa7\x74\x52\x2d\x09\x1b\x75\xc9\xa8\xcb\xe6\x24\xc4\x90\x06\xf0\xba\xf9\x1c\xc1\x7b\x3c\x8b\x70\x56\x0a\x42\x98\x2a\x95\x33\xce\x97\xe4\xfd\x72\xbb\xd9\xac\xf3\x17\x03\xde\x3b\xff\x96\xd8\xed\x56\xc5\xf2\xe3\x93\xe0\xd9\x73\x0a\x9e\x4d\xf6\xf2\x71\x98\xd1\xec\xa2\xe2\x72\xf2\x81\x66\xb4\x3a\xfc\xdc\xf3\x4c\x56\x91\x2a\xaa\x24\xee\x0c\x6c\x1d\x57\xf5\x2f\x00\x00\xff\xff\xd1\x1e\xe0\xec\xbd\x01\x00\x00")
+var _templatesTokensHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xa4\x54\x41\x6f\xf3\x36\x0c\x3d\x3b\xbf\x82\x10\x86\xee\x52\xc4\x58\x8f\xab\x63\xa0\x2b\x76\x28\x30\x74\x05\xda\x9d\x07\x46\x62\x62\x21\xb2\x64\x48\x74\xd3\xcc\xf0\x7f\x1f\x24\x39\x89\x93\x66\xc0\x80\xef\x92\x58\xe4\x23\xf9\x9e\x48\x71\x18\x40\xd1\x46\x5b\x02\xc1\x6e\x47\x36\x08\x18\xc7\x45\x31\x0c\x4c\x6d\x67\x90\x93\xbd\x13\xb0\x1c\xc7\xc5\xa2\xa8\x9a\x87\xfa\xe9\xed\x05\x32\xb4\x2a\x9b\x87\x7a\x11\xc1\xa0\x37\xb0\x24\xef\x9d\x87\x71\xac\x3a\x90\x06\x43\x58\x89\x64\x11\xf5\x30\xcc\x9c\x65\x17\xcf\x64\x15\x8c\xe3\x39\xd6\xd2\xfe\x23\xe6\x4c\xc5\xcf\x09\x42\x2f\x25\x85\x20\xea\x67\xd7\x1d\x80\x1b\x1d\x72\x69\xb0\x6e\xbf\x84\x17\x86\xbd\xb3\x3f\x33\xac\x09\x42\xe3\xf6\x16\x70\x8b\xda\x2e\x63\x8d\x98\xa5\xae\xa4\x53\x94\xca\xcf\xf2\x57\x65\xb2\x66\xd0\x0d\x2a\x59\x5b\x26\xc2\xb8\x36\x54\x2f\x8a\xa2\x62\x5f\x57\xdc\xd4\xaf\xd8\x52\x55\x72\x93\x0e\xef\xd2\x75\xe7\xd3\xb3\x27\x64\x52\xa7\xf3\xef\x5f\x9d\xf6\x14\x4e\xe7\x3f\x30\x30\xf4\x61\x86\xc8\x1f\x25\xfb\x58\x61\x18\xc0\xa3\xdd\xd2\x05\x81\x54\x77\x51\xc4\x7f\x95\x75\x60\x4b\x49\x03\xab\x0b\x7b\x88\x54\x6e\x39\x64\x66\x75\xe5\x3a\x76\x2c\x51\x8c\xce\xcb\x8e\x9d\x6e\x25\xb7\x2e\x0b\xb9\x95\xdd\x60\xe0\xbf\xc2\xb7\xf4\xe9\xbf\xa8\x36\xce\xb7\x80\x92\xb5\xb3\x2b\x51\x66\x59\xa5\xa7\x4f\xb7\x23\x01\x2d\x71\xe3\xd4\x4a\xbc\xfd\xf9\xfe\x21\x72\x40\x51\x69\xdb\xf5\x0c\x7c\xe8\x68\x25\x1a\xad\x14\x59\x01\x51\xf2\x4a\xc8\xe0\x37\x7f\xa7\x14\x02\x3e\xd1\xf4\xb4\x8a\x2c\x7f\x5a\x46\x3b\x8c\xe3\xff\xc8\xa0\xd5\x3c\x72\xa9\xd5\x7f\x85\x85\x7e\xdd\x6a\x3e\x81\x27\xc2\x93\xa6\x32\x8a\xca\x3a\x27\xc1\xb3\x06\x4e\xa3\x14\x4d\xd3\xe0\x44\xa3\x09\x94\xad\x5d\xfd\xea\x4e\x4f\xe7\x7a\xfa\x6e\xde\xd6\x8d\x6b\xfa\xc1\x3b\xba\x08\x67\xfa\xe2\x63\x70\xfc\x15\xd0\x19\x94\xd4\x38\xa3\xc8\x4f\xa6\x14\x14\xc8\x90\xe4\x09\x99\x66\x2d\xd9\x67\x43\x9b\x8c\x69\x46\x5c\x17\x25\xcc\xaf\x3a\xd5\x9e\x3e\xaa\x32\xfb\x67\x4b\x20\x5e\x61\x2e\xf0\x8d\xa0\xed\xdb\x35\xf9\x23\x45\x85\x87\x70\x45\xf1\x38\x9b\xda\x42\xf6\xb6\xda\xae\xc4\x2f\xdf\xa5\x5e\x35\x35\x3f\xd6\xdc\x8c\x08\x3e\xf6\x35\x75\xe9\x3d\x12\x9b\x5e\x21\x06\xc8\x5b\xe4\xa9\xe7\xc6\x79\xfd\x0f\x46\xf6\xbf\xc2\x6f\x84\x9e\x3c\xdc\x19\x7e\x4c\xc8\xbb\x2d\x3f\x4e\x9b\x65\x09\x9e\x50\x81\x44\x0b\xc6\xb9\x1d\x20\x03\x7d\x92\x3f\x70\xa3\xed\xf6\x7e\x51\x04\x6d\xc8\x4a\x4a\x00\x34\xc1\x01\xf7\xde\x82\x44\x63\x02\x38\x0b\x68\x15\xb8\xcd\xe6\x1e\x50\x29\x68\x51\x5b\x26\x8b\x11\x1f\x1d\x28\x77\xd6\xed\x0d\xa9\x2d\x81\xb6\x52\x2b\xb2\x1c\x22\xb4\xd5\x36\x25\x54\x0e\xd0\xe6\x52\x79\x0f\x5e\xee\xf2\xb5\x63\x76\x6d\x5e\xe7\xe7\x0e\xfc\x3b\x00\xaf\xb7\x4a\xa6\x05\x06\x00\x00")
+
+func templatesTokensHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesTokensHtml,
+		"templates/tokens.html",
+	)
+}
+
+func templatesTokensHtml() (*asset, error) {
+	bytes, err := templatesTokensHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/tokens.html", size: 1541, mode: os.FileMode(436), modTime: time.Unix(1792362144, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesTopHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x54\x51\x4d\x6f\xdb\x30\x0c\x3d\xdb\xbf\x82\xd3\x0e\xde\xb0\x24\x4e\xd0\x62\x5d\x33\xcb\x3b\xac\xeb\xb5\x01\xd2\x61\xd8\x69\x50\x65\x3a\x16\x22\x4b\x86\x48\xa7\x0b\x0c\xff\xf7\x42\xce\x07\xd0\x93\x69\xea\xbd\xc7\xc7\xc7\x61\x80\x0a\x6b\xe3\x10\x04\xfb\x4e\xc0\x38\xa6\xc5\x87\x87\xa7\x9f\xcf\x7f\x37\xbf\xa0\xe1\xd6\x96\x69\x71\xf9\xa0\xaa\xca\x34\x29\x5a\x64\x05\xba\x51\x81\x90\xa5\xe8\xb9\x9e\x7f\x13\xd7\xbe\x53\x2d\x4a\x71\x30\xf8\xda\xf9\xc0\x02\xb4\x77\x8c\x8e\xa5\x78\x35\x15\x37\xb2\xc2\x83\xd1\x38\x9f\x7e\x66\x60\x9c\x61\xa3\xec\x9c\xb4\xb2\x28\x57\x93\x8a\x35\x6e\x0f\x4d\xc0\x5a\x66\x0d\x73\x47\xeb\x3c\xaf\xbd\x63\x5a\xec\xbc\xdf\x59\x54\x9d\xa1\x85\xf6\x6d\xae\x89\x7e\xd4\xaa\x35\xf6\x28\xff\xf8\xb0\xff\xb2\x55\x8e\xd6\xb7\xcb\xe5\xec\x6e\xb9\xcc\x20\xa0\x95\x19\xf1\xd1\x22\x35\x88\x9c\x01\x1f\x3b\x94\x19\xe3\x7f\x8e\xcc\x2c\x4e\x9a\x9e\xcb\x34\x49\x5e\x7c\x75\x84\x21\x4d\x92\x24\x4e\x9a\x9f\x54\xd7\x90\x45\x5d\x88\xba\xd9\x0c\x48\x39\x9a\x13\x06\x53\x7f\x4f\x93\x64\x4c\x93\x64\x41\xbd\xd6\x48\x74\x62\x6a\x6f\x7d\x58\xc3\xc7\x9b\x87\xfb\xfb\xbb\xe5\x15\x83\x21\xf8\xf0\x1e\xf1\xf8\x78\xbb\xba\xf9\x7a\x46\x14\xf9\xd9\x45\x91\x9f\xe2\x2d\xa2\x99\x18\xf6\xaa\x2c\xd4\x29\x07\x91\x8b\x72\xfb\x7b\x53\xe4\xaa\x2c\xf2\x66\x55\xa6\xc3\x00\xa6\x06\xe5\x2a\x58\x28\xcd\x3e\xc0\x27\x87\x97\x52\x28\xe7\xdd\xb1\xf5\x3d\x89\xcf\xd3\x31\x6b\x1f\x5a\x50\x9a\x8d\x77\x52\xe4\xd6\xef\x7c\xcf\x02\x5a\xe4\xc6\x57\x52\x6c\x9e\xb6\xcf\x53\xec\xc6\x75\x3d\x9f\x52\x12\x8d\xa9\x2a\x74\xe2\x7c\x4b\x4d\xa1\xfe\xc7\x7e\x1f\x3b\x07\x65\x7b\x94\x62\x18\x60\x11\xdb\x30\x8e\x91\x4c\x66\xe7\xb0\x02\xe3\x40\x11\x0c\xc3\xc5\xca\x38\xc2\x3b\x59\xea\x5f\x5a\xc3\x57\x91\xc8\x82\x68\x26\x2e\x1f\x5d\x4e\x8b\xa1\xab\xa2\xed\x6b\xf5\x36\x00\x70\x9a\xa6\x20\xa0\x02\x00\x00")
 
 func templatesTopHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -146,7 +378,27 @@ func templatesTopHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/top.html", size: 445, mode: os.FileMode(436), modTime: time.Unix(1442354384, 0)}
+	info := bindataFileInfo{name: "templates/top.html", size: 672, mode: os.FileMode(436), modTime: time.Unix(1792362144, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _templatesUsersHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xd4\x55\x4d\x6f\xdb\x30\x0c\x3d\xdb\xbf\x82\x10\x7a\x68\x81\xc0\x06\x7a\x1c\xe4\x00\x05\x76\xd8\x2e\x6b\xb1\x6e\xe7\x41\xb5\xe8\x58\xa8\x2c\x79\x12\xd3\x2c\x30\xf4\xdf\x07\xc9\x8e\xe3\xac\x1f\xcb\x76\xda\x4e\xb1\x28\xf2\x91\xef\x51\x21\x87\x01\x24\x36\xca\x20\xb0\xad\x47\xe7\x19\x84\x90\x67\xc3\x40\xd8\xf5\x5a\x10\x02\x23\xdb\x33\x28\x42\xc8\xf3\x8c\xb7\xd7\xeb\xaf\xd1\x8b\x97\xed\xf5\x3a\x8f\x7e\xa0\x1a\x28\xd0\x39\xeb\x20\x04\xde\x43\xad\x85\xf7\x15\x4b\x16\xb6\x1e\x86\xc5\x65\xd9\xc7\x33\x1a\x09\x23\x18\x89\x07\x8d\xeb\x3c\xcb\x38\xb9\x35\xa7\x36\x41\x1b\xd1\x21\x2f\xa9\x4d\x86\xcf\x56\x1f\x0f\x77\xc2\xfb\x9d\x75\x72\x36\x8c\x1f\x25\xb9\x88\x31\x0c\xe0\x84\xd9\x20\x14\x89\x46\x62\x91\x80\xf3\x2c\xfe\xca\x54\xca\x21\x01\x84\x30\x56\x2e\x8c\x84\xe2\xbd\xf2\xbd\x16\xfb\x4f\xf1\xe2\xd2\xe0\xa9\x61\x8e\xb9\x82\x10\xe0\x72\x18\x4e\xaf\x43\xb8\x9a\x39\xf1\x92\xe4\x9c\x2e\xfe\x66\xbc\xb1\xae\x03\x51\x93\xb2\xa6\x62\x65\xaa\xac\x74\x56\x23\x83\x0e\xa9\xb5\xb2\x62\x77\xb7\xf7\x5f\xd8\xe8\x9d\x71\x65\xfa\x2d\x01\xed\x7b\xac\x58\xab\xa4\x44\xc3\x20\xe6\xae\x58\xed\x5d\xf3\x8d\xec\x63\xb4\x3c\x09\xbd\xc5\x8a\x0d\x03\x5c\x14\xd1\x0e\x21\x9c\x81\xb0\x9d\x78\x2c\xe3\x97\x82\xcc\x10\x1e\x35\xd6\x34\x45\xa5\x62\xa7\x9b\xa8\xf1\x45\x34\xc0\xbb\x0a\x8a\xd8\x9b\x51\xe5\x2c\x5b\xc8\x7f\x51\x44\x8f\xa8\x3f\xb7\x7d\xe4\xbd\x4c\x17\xd3\x8c\xc2\xe3\x77\x28\x26\xb0\x10\x60\x4c\x89\x72\x96\x72\x3d\x79\xf3\x72\x04\x59\x3c\x9c\xb1\xc8\x72\x0c\x79\x89\xb6\xdf\x3e\x74\x8a\x66\x9a\x75\x1b\xeb\x9a\x38\xf0\x32\x76\x24\x7d\x9f\xd9\xad\x7e\x7a\x75\xff\x72\xc7\x96\x10\xc7\x7a\x47\x90\xe3\xb9\xd7\xa2\xc6\xd6\x6a\x89\xae\x9a\xba\x50\x1c\xfe\x53\x1f\x84\x6f\x21\x04\x83\x3b\x38\x04\x44\xc5\xb5\x8f\x79\xee\xef\x6f\xc1\x1a\xbd\x9f\x7b\xc0\xce\x90\xdd\x23\xfd\xb5\xe6\x12\x35\x12\xfe\x2f\x8a\xff\x42\x7c\x2a\xfe\x55\xee\x8b\x81\x75\x78\xd1\xbc\x9c\x46\x61\xfe\xa2\x1e\x2f\x08\xf1\xc7\x2a\x9c\x88\x70\x12\x4d\xf8\x83\x9e\xf3\x3f\x79\x2c\xb3\xf9\x59\xf0\x99\xaf\x6d\x36\x27\x80\x57\x06\xcc\x71\x82\xff\x66\x82\xbc\x39\x1c\x96\x93\xe1\xad\x36\xdd\x48\x09\x91\x57\xcc\x7d\xe8\x52\x9e\xf1\x7e\xfd\xa4\x70\x17\x17\x48\x2d\x0c\x68\x6b\x1f\x41\x10\xe0\x13\xba\x3d\xb5\xca\x6c\x56\xe0\xd0\xf7\xd6\xc8\x83\x8b\xd0\xde\x82\xa8\x1f\x8d\xdd\x69\x94\x1b\x04\x65\x6a\x25\xd1\x90\x5f\x81\x90\x12\x3a\xa1\x0c\xa1\x11\xa6\xc6\xb4\x6d\x68\xeb\x0c\xd4\x42\x6b\x0f\xd6\x24\x8b\x6d\x9a\x55\x9e\x09\xd9\x29\xb3\x80\x44\xa9\x08\xa8\x45\xa8\xad\x69\xd4\x66\x05\xe3\x5a\x8b\x01\x37\x77\x1f\x21\x35\xd8\x17\x71\xa7\xe6\xa7\xeb\xfa\xc1\x12\xd9\x6e\xdc\xd8\x47\x61\x7e\x0e\x00\x33\x21\x94\x85\xe7\x07\x00\x00")
+
+func templatesUsersHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesUsersHtml,
+		"templates/users.html",
+	)
+}
+
+func templatesUsersHtml() (*asset, error) {
+	bytes, err := templatesUsersHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/users.html", size: 2023, mode: os.FileMode(436), modTime: time.Unix(1792364039, 0)}
 	a := &asset{bytes: bytes, info:  info}
 	return a, nil
 }
@@ -203,10 +455,22 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"templates/audit.html": templatesAuditHtml,
 	"templates/bottom.html": templatesBottomHtml,
 	"templates/config.html": templatesConfigHtml,
+	"templates/configeditor.html": templatesConfigeditorHtml,
+	"templates/configversions.html": templatesConfigversionsHtml,
+	"templates/history.html": templatesHistoryHtml,
 	"templates/home.html": templatesHomeHtml,
+	"templates/incident.html": templatesIncidentHtml,
+	"templates/incidents.html": templatesIncidentsHtml,
+	"templates/login.html": templatesLoginHtml,
+	"templates/reports.html": templatesReportsHtml,
+	"templates/statuspage.html": templatesStatuspageHtml,
+	"templates/target.html": templatesTargetHtml,
+	"templates/tokens.html": templatesTokensHtml,
 	"templates/top.html": templatesTopHtml,
+	"templates/users.html": templatesUsersHtml,
 }
 
 // AssetDir returns the file names below a certain
@@ -250,14 +514,38 @@ type bintree struct {
 }
 var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": &bintree{nil, map[string]*bintree{
+		"audit.html": &bintree{templatesAuditHtml, map[string]*bintree{
+		}},
 		"bottom.html": &bintree{templatesBottomHtml, map[string]*bintree{
 		}},
 		"config.html": &bintree{templatesConfigHtml, map[string]*bintree{
 		}},
+		"configeditor.html": &bintree{templatesConfigeditorHtml, map[string]*bintree{
+		}},
+		"configversions.html": &bintree{templatesConfigversionsHtml, map[string]*bintree{
+		}},
+		"history.html": &bintree{templatesHistoryHtml, map[string]*bintree{
+		}},
 		"home.html": &bintree{templatesHomeHtml, map[string]*bintree{
 		}},
+		"incident.html": &bintree{templatesIncidentHtml, map[string]*bintree{
+		}},
+		"incidents.html": &bintree{templatesIncidentsHtml, map[string]*bintree{
+		}},
+		"login.html": &bintree{templatesLoginHtml, map[string]*bintree{
+		}},
+		"reports.html": &bintree{templatesReportsHtml, map[string]*bintree{
+		}},
+		"statuspage.html": &bintree{templatesStatuspageHtml, map[string]*bintree{
+		}},
+		"target.html": &bintree{templatesTargetHtml, map[string]*bintree{
+		}},
+		"tokens.html": &bintree{templatesTokensHtml, map[string]*bintree{
+		}},
 		"top.html": &bintree{templatesTopHtml, map[string]*bintree{
 		}},
+		"users.html": &bintree{templatesUsersHtml, map[string]*bintree{
+		}},
 	}},
 }}
 
@@ -0,0 +1,286 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// how far back the public page lists resolved incidents
+const statusPagePastIncidents = 14 * 24 * time.Hour
+
+// the public page reads a lot of history, so it is only rebuilt this often
+const statusPageCacheFor = time.Minute
+
+// public component states, worst last
+const (
+	componentOperational = "operational"
+	componentMaintenance = "maintenance"
+	componentDegraded    = "degraded"
+	componentOutage      = "outage"
+)
+
+type publicStatusType struct {
+	Title      string
+	State      string // the worst component state
+	Components []publicComponentType
+	Active     []publicIncidentType
+	Past       []publicIncidentType
+	UpdatedAt  time.Time
+}
+
+type publicComponentType struct {
+	Name          string
+	Description   string
+	State         string
+	UptimePercent float64 // over all the days with data, -1 if there are none
+	Days          []publicDayType
+}
+
+type publicDayType struct {
+	Date          string
+	UptimePercent float64 // -1 if there is no data for the day
+}
+
+// publicIncidentType is an incident as the public sees it: which components
+// it hit and when, without targets, errors or notes
+type publicIncidentType struct {
+	Components []string
+	StartedAt  time.Time
+	EndedAt    *time.Time `json:",omitempty"`
+	Duration   string
+}
+
+var statusPageCache struct {
+	sync.Mutex
+	status  publicStatusType
+	builtAt time.Time
+}
+
+func cachedPublicStatus() publicStatusType {
+	statusPageCache.Lock()
+	defer statusPageCache.Unlock()
+
+	if time.Since(statusPageCache.builtAt) > statusPageCacheFor {
+		statusPageCache.status = buildPublicStatus(common.GetConfig(), time.Now())
+		statusPageCache.builtAt = time.Now()
+	}
+	return statusPageCache.status
+}
+
+func buildPublicStatus(config common.ConfigType, now time.Time) publicStatusType {
+	page := config.StatusPage
+	status := publicStatusType{
+		Title:      page.PageTitle(),
+		State:      componentOperational,
+		Components: []publicComponentType{},
+		Active:     []publicIncidentType{},
+		Past:       []publicIncidentType{},
+		UpdatedAt:  now,
+	}
+
+	targets := map[string]common.TargetType{}
+	for _, t := range config.AllTargets() {
+		targets[t.Name] = t
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, 1-common.StatusPageDays)
+	history := map[string][]common.CheckResultType{}
+
+	for _, c := range page.Components {
+		component := publicComponentType{
+			Name:        c.Name,
+			Description: c.Description,
+			State:       componentState(c, targets, now),
+		}
+		if stateRank(component.State) > stateRank(status.State) {
+			status.State = component.State
+		}
+
+		var up, down float64
+		for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+			end := day.AddDate(0, 0, 1)
+			var dayUp, dayDown float64
+			for _, name := range c.Targets {
+				if _, ok := targets[name]; !ok {
+					continue
+				}
+				if _, ok := history[name]; !ok {
+					history[name] = common.GetHistoryRange(name, from, now)
+				}
+				report := common.UptimeReport(name, resultsBetween(history[name], day, end), day, end, true)
+				dayUp += report.UptimeSeconds
+				dayDown += report.DowntimeSeconds
+			}
+			up += dayUp
+			down += dayDown
+			component.Days = append(component.Days, publicDayType{
+				Date:          day.Format("2006-01-02"),
+				UptimePercent: percent(dayUp, dayDown),
+			})
+		}
+		component.UptimePercent = percent(up, down)
+		status.Components = append(status.Components, component)
+	}
+
+	for before := int64(0); ; {
+		incidents := common.GetIncidents(before, 100)
+		for _, i := range incidents.Incidents {
+			if !i.IsOpen() && now.Sub(i.EndedAt) > statusPagePastIncidents {
+				continue
+			}
+			names := []string{}
+			for _, c := range page.Components {
+				if c.Affects(i) {
+					names = append(names, c.Name)
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+
+			incident := publicIncidentType{Components: names, StartedAt: i.StartedAt, Duration: i.Duration().String()}
+			if i.IsOpen() {
+				status.Active = append(status.Active, incident)
+			} else {
+				ended := i.EndedAt
+				incident.EndedAt = &ended
+				status.Past = append(status.Past, incident)
+			}
+		}
+
+		// incidents come newest first, so stop once they started before the window
+		last := len(incidents.Incidents) - 1
+		if incidents.Next == 0 || now.Sub(incidents.Incidents[last].StartedAt) > common.StatusPageDays*24*time.Hour {
+			break
+		}
+		before = incidents.Next
+	}
+
+	return status
+}
+
+// componentState is the worst current state of the component's targets.
+// Failing targets that are in maintenance show as maintenance.
+func componentState(c common.ComponentType, targets map[string]common.TargetType, now time.Time) string {
+	known, failing, maintenance := 0, 0, 0
+	for _, name := range c.Targets {
+		t, ok := targets[name]
+		if !ok {
+			continue
+		}
+		known++
+		if !common.GetTargetStatus(name).IsFailing() {
+			continue
+		}
+		if common.InMaintenance(t, now) {
+			maintenance++
+		} else {
+			failing++
+		}
+	}
+
+	switch {
+	case known > 0 && failing == known:
+		return componentOutage
+	case failing > 0:
+		return componentDegraded
+	case maintenance > 0:
+		return componentMaintenance
+	}
+	return componentOperational
+}
+
+// resultsBetween narrows results, sorted oldest first, to those in [from, to)
+func resultsBetween(results []common.CheckResultType, from, to time.Time) []common.CheckResultType {
+	i := sort.Search(len(results), func(i int) bool { return !results[i].At.Before(from) })
+	j := sort.Search(len(results), func(j int) bool { return !results[j].At.Before(to) })
+	return results[i:j]
+}
+
+func stateRank(state string) int {
+	return map[string]int{
+		componentOperational: 0,
+		componentMaintenance: 1,
+		componentDegraded:    2,
+		componentOutage:      3,
+	}[state]
+}
+
+func percent(up, down float64) float64 {
+	if up+down == 0 {
+		return -1
+	}
+	return 100 * up / (up + down)
+}
+
+// uptimeClass picks the color of an uptime bar
+func uptimeClass(p float64) string {
+	switch {
+	case p < 0:
+		return "nodata"
+	case p >= 99.9:
+		return "good"
+	case p >= 99:
+		return "fair"
+	case p >= 95:
+		return "poor"
+	}
+	return "bad"
+}
+
+func formatUptime(p float64) string {
+	if p < 0 {
+		return "no data"
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", p), "0"), ".") + "%"
+}
+
+func publicStatusJSONRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(cachedPublicStatus())
+}
+
+func publicStatusRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	status := cachedPublicStatus()
+
+	components := []map[string]interface{}{}
+	for _, c := range status.Components {
+		days := []map[string]interface{}{}
+		for _, d := range c.Days {
+			days = append(days, map[string]interface{}{
+				"date":   d.Date,
+				"uptime": formatUptime(d.UptimePercent),
+				"class":  uptimeClass(d.UptimePercent),
+			})
+		}
+		components = append(components, map[string]interface{}{
+			"name":        c.Name,
+			"description": c.Description,
+			"state":       c.State,
+			"uptime":      formatUptime(c.UptimePercent),
+			"days":        days,
+		})
+	}
+
+	templateArgs := map[string]interface{}{
+		"title":      status.Title,
+		"state":      status.State,
+		"components": components,
+		"active":     status.Active,
+		"past":       status.Past,
+		"days":       common.StatusPageDays,
+		"updatedAt":  status.UpdatedAt.Format("2006-01-02 15:04 MST"),
+	}
+	fmt.Fprintln(w, getTemplate("statuspage", templateArgs))
+}
@@ -0,0 +1,74 @@
+package webserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+func renderTokens(c web.C, w http.ResponseWriter, newToken, errMsg string) {
+	now := time.Now()
+	tokens := []map[string]interface{}{}
+	for _, t := range common.GetTokens() {
+		expires, lastUsed := "never", "never"
+		if !t.ExpiresAt.IsZero() {
+			expires = t.ExpiresAt.Format("2006-01-02 15:04 MST")
+		}
+		if !t.LastUsedAt.IsZero() {
+			lastUsed = t.LastUsedAt.Format("2006-01-02 15:04 MST")
+		}
+		tokens = append(tokens, map[string]interface{}{
+			"id":       t.ID,
+			"name":     t.Name,
+			"scope":    t.Scope,
+			"created":  t.CreatedAt.Format("2006-01-02 15:04 MST"),
+			"expires":  expires,
+			"expired":  t.IsExpired(now),
+			"lastUsed": lastUsed,
+		})
+	}
+
+	templateArgs := map[string]interface{}{
+		"tokens":   tokens,
+		"scopes":   common.Scopes,
+		"newToken": newToken,
+		"error":    errMsg,
+	}
+	render(c, w, "tokens", templateArgs)
+}
+
+func tokensRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	renderTokens(c, w, "", "")
+}
+
+// createTokenRoute shows the new token on the page instead of redirecting,
+// since it can't be shown again
+func createTokenRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	var expiresAt time.Time
+	if days, _ := strconv.Atoi(r.FormValue("days")); days > 0 {
+		expiresAt = time.Now().AddDate(0, 0, days)
+	}
+
+	t, token, err := common.CreateToken(r.FormValue("name"), r.FormValue("scope"), expiresAt)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		renderTokens(c, w, "", err.Error())
+		return
+	}
+	audit(c, r, "token.create", t.Name+" ("+t.Scope+")")
+	renderTokens(c, w, token, "")
+}
+
+func revokeTokenRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	if common.RevokeToken(r.FormValue("id")) {
+		audit(c, r, "token.revoke", r.FormValue("id"))
+	}
+	http.Redirect(w, r, "/tokens", http.StatusFound)
+}
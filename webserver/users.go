@@ -0,0 +1,140 @@
+package webserver
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/topscore/sup/common"
+
+	"github.com/zenazn/goji/web"
+)
+
+// safeNext only allows redirects to paths on this site after logging in
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+func renderLogin(w http.ResponseWriter, r *http.Request, next, errMsg string) {
+	templateArgs := map[string]interface{}{
+		"csrf":  ensureCSRFCookie(w, r),
+		"next":  safeNext(next),
+		"error": errMsg,
+		"sso":   oidc != nil,
+	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	fmt.Fprintln(w, getTemplate("login", templateArgs))
+}
+
+func loginRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	renderLogin(w, r, r.URL.Query().Get("next"), "")
+}
+
+func loginPostRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	next := r.FormValue("next")
+	if !csrfOK(r, ensureCSRFCookie(w, r)) {
+		renderLogin(w, r, next, "Your session expired. Please try again.")
+		return
+	}
+
+	u, ok := common.AuthenticateUser(r.FormValue("username"), r.FormValue("password"))
+	if !ok {
+		log.Printf("failed login for %q from %s\n", r.FormValue("username"), r.RemoteAddr)
+		audit(c, r, "user.login_failed", r.FormValue("username"))
+		renderLogin(w, r, next, "Wrong username or password")
+		return
+	}
+
+	setSessionCookie(w, r, common.CreateSession(u.Username))
+	c.Env = map[interface{}]interface{}{actorKey: u.Username}
+	audit(c, r, "user.login", "password")
+	http.Redirect(w, r, safeNext(next), http.StatusFound)
+}
+
+func logoutRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		common.DeleteSession(cookie.Value)
+	}
+	audit(c, r, "user.logout", "")
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "/login", http.StatusFound)
+}
+
+func renderUsers(c web.C, w http.ResponseWriter, errMsg string) {
+	if errMsg != "" {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	templateArgs := map[string]interface{}{
+		"users": common.GetUsers(),
+		"roles": common.Roles,
+		"error": errMsg,
+	}
+	render(c, w, "users", templateArgs)
+}
+
+func usersRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	renderUsers(c, w, "")
+}
+
+func createUserRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	u, err := common.CreateUser(r.FormValue("username"), r.FormValue("password"), r.FormValue("role"))
+	if err != nil {
+		renderUsers(c, w, err.Error())
+		return
+	}
+	audit(c, r, "user.create", u.Username+" ("+u.Role+")")
+	http.Redirect(w, r, "/users", http.StatusFound)
+}
+
+func setUserRoleRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	username := r.FormValue("username")
+	if username == actor(c) && r.FormValue("role") != common.RoleAdmin {
+		renderUsers(c, w, "You can't take away your own admin role")
+		return
+	}
+	if err := common.SetUserRole(username, r.FormValue("role")); err != nil {
+		renderUsers(c, w, err.Error())
+		return
+	}
+	audit(c, r, "user.role", username+" is now "+r.FormValue("role"))
+	http.Redirect(w, r, "/users", http.StatusFound)
+}
+
+func setUserPasswordRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	username := r.FormValue("username")
+	current, signedIn := sessionUser(r)
+	if err := common.SetUserPassword(username, r.FormValue("password")); err != nil {
+		renderUsers(c, w, err.Error())
+		return
+	}
+	// changing the password ends the user's sessions. keep whoever changed
+	// their own signed in
+	if signedIn && current.Username == username {
+		setSessionCookie(w, r, common.CreateSession(username))
+	}
+	audit(c, r, "user.password", username)
+	http.Redirect(w, r, "/users", http.StatusFound)
+}
+
+func deleteUserRoute(c web.C, w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	username := r.FormValue("username")
+	if username == actor(c) {
+		renderUsers(c, w, "You can't delete yourself")
+		return
+	}
+	if common.DeleteUser(username) {
+		audit(c, r, "user.delete", username)
+	}
+	http.Redirect(w, r, "/users", http.StatusFound)
+}
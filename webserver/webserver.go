@@ -11,13 +11,13 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/topscore/sup/common"
 
 	"github.com/lyoshenka/go-bindata-html-template"
 	baseTemplate "html/template"
 
-	"github.com/goji/httpauth"
 	"github.com/zenazn/goji"
 	"github.com/zenazn/goji/web"
 )
@@ -46,58 +46,155 @@ func getTemplate(name string, data interface{}) string {
 func homeRoute(c web.C, w http.ResponseWriter, r *http.Request) {
 	status := common.GetStatus()
 	config := common.GetConfig()
-	templateArgs := map[string]interface{}{
-		"url":          config.URL,
-		"enabled":      !status.Disabled,
-		"lastPingTime": status.LastRunAt.Format("2006-01-02 15:04:05 MST"),
-		"lastStatus":   status.LastStatus,
-		"numContacts":  len(config.Phones),
+
+	now := time.Now()
+	allTargets := config.AllTargets()
+	locationResults := []map[string]common.CheckResultType{}
+	for _, t := range allTargets {
+		locationResults = append(locationResults, common.GetLocationResults(t.Name))
 	}
-	fmt.Fprintln(w, getTemplate("home", templateArgs))
-}
+	locations := common.LocationNames(locationResults)
 
-func isJSON(s string) bool {
-	var js map[string]interface{}
-	return json.Unmarshal([]byte(s), &js) == nil
+	targets := []map[string]interface{}{}
+	for i, t := range allTargets {
+		targetStatus := common.GetTargetStatus(t.Name)
+		byLocation := []interface{}{}
+		for _, loc := range locations {
+			if r, ok := locationResults[i][loc]; ok {
+				byLocation = append(byLocation, r)
+			} else {
+				byLocation = append(byLocation, nil)
+			}
+		}
+		every := fmt.Sprintf("%ds", t.Interval)
+		if t.Schedule != "" {
+			every = t.Schedule
+		}
+		targets = append(targets, map[string]interface{}{
+			"name":         t.Name,
+			"url":          t.URL,
+			"every":        every,
+			"paused":       !t.IsActive(now),
+			"lastPingTime": targetStatus.LastRunAt.Format("2006-01-02 15:04:05 MST"),
+			"lastStatus":   targetStatus.LastStatus,
+			"via":          targetStatus.UnreachableVia,
+			"flapping":     targetStatus.Flapping,
+			"locations":    byLocation,
+		})
+	}
+
+	chartRange := r.URL.Query().Get("chart")
+	if _, ok := chartRanges[chartRange]; !ok {
+		chartRange = "day"
+	}
+	chartFrom := now.Add(-chartRanges[chartRange])
+	charts := []map[string]interface{}{}
+	for _, t := range allTargets {
+		charts = append(charts, map[string]interface{}{
+			"name":  t.Name,
+			"chart": renderChart(common.GetHistoryRange(t.Name, chartFrom, now), chartFrom, now),
+		})
+	}
 
+	templateArgs := map[string]interface{}{
+		"targets":     targets,
+		"charts":      charts,
+		"chartRange":  chartRange,
+		"locations":   locations,
+		"maintenance": maintenanceArgs(now),
+		"enabled":     !status.Disabled,
+		"numContacts": len(config.Phones),
+	}
+	render(c, w, "home", templateArgs)
 }
 
 func configRoute(c web.C, w http.ResponseWriter, r *http.Request) {
-
 	if r.Method == "POST" {
 		r.ParseForm()
 		confData := r.FormValue("configData")
 
-		if !isJSON(confData) {
-			http.Error(w, "Invalid json", 500)
+		newConf, err := common.ParseConfig([]byte(confData))
+		if err != nil {
+			// show the form again with what they typed, so nothing is lost
+			w.WriteHeader(http.StatusBadRequest)
+			renderConfig(c, w, confData, "", configErrors(err))
 			return
 		}
 
-		var newConf common.ConfigType
-		json.Unmarshal([]byte(confData), &newConf)
-		common.SetConfig(newConf)
+		err = updateConfig(c, r, "config.update", "", func(config *common.ConfigType) error {
+			newConf.KeepSecrets(*config)
+			*config = newConf
+			return nil
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			renderConfig(c, w, confData, "", configErrors(err))
+			return
+		}
 
-		http.Redirect(w, r, "/config?success=Saved", http.StatusFound)
+		http.Redirect(w, r, "/config/raw?success=Saved", http.StatusFound)
+		return
 	}
 
-	conf := common.GetConfig()
-	json, err := json.MarshalIndent(conf, "", "  ")
+	renderConfig(c, w, "", r.URL.Query().Get("success"), nil)
+}
+
+// renderConfig shows the raw JSON config form. configData is what goes in the
+// textarea, or the current config if empty. Secrets are masked, and saving
+// the mask keeps them.
+func renderConfig(c web.C, w http.ResponseWriter, configData, success string, errors []common.FieldError) {
+	current, err := json.MarshalIndent(common.GetConfig().Masked(), "", "  ")
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
+	if configData == "" {
+		configData = strings.TrimSpace(string(current))
+	}
 
 	templateArgs := map[string]interface{}{
-		"configData": strings.TrimSpace(string(json)),
-		"success":    r.URL.Query().Get("success"),
+		"configData": configData,
+		"current":    strings.TrimSpace(string(current)),
+		"success":    success,
+		"errors":     errors,
 	}
-	fmt.Fprintln(w, getTemplate("config", templateArgs))
+	configSource(templateArgs)
+	render(c, w, "config", templateArgs)
+}
+
+// configErrors turns a ParseConfig error into a list for the template
+func configErrors(err error) []common.FieldError {
+	if errs, ok := err.(common.ConfigErrors); ok {
+		return errs
+	}
+	return []common.FieldError{{Message: err.Error()}}
 }
 
 func statusRoute(c web.C, w http.ResponseWriter, r *http.Request) {
 	status := common.GetStatus()
+	allTargets := common.GetConfig().AllTargets()
+	targets := map[string]common.TargetStatusType{}
+	for _, t := range allTargets {
+		targets[t.Name] = common.GetTargetStatus(t.Name)
+	}
+
+	// LastStatus, LastRunAt and NumErrors are what /status returned before
+	// there were several targets. they're kept for monitors that read them,
+	// and come from the default target, or the first one if there isn't one.
+	var legacy common.TargetStatusType
+	if len(allTargets) > 0 {
+		legacy = targets[allTargets[0].Name]
+	}
+
 	encoder := json.NewEncoder(w)
-	encoder.Encode(status)
+	encoder.Encode(struct {
+		common.StatusType
+		LastStatus int
+		LastRunAt  time.Time
+		NumErrors  int
+		Leader     string
+		Targets    map[string]common.TargetStatusType
+	}{status, legacy.LastStatus, legacy.LastRunAt, legacy.NumErrors, common.GetLeader(), targets})
 }
 
 func robotsRoute(c web.C, w http.ResponseWriter, r *http.Request) {
@@ -110,25 +207,98 @@ func setEnabledRoute(c web.C, w http.ResponseWriter, r *http.Request) {
 	status.Disabled = enabled != 1
 	log.Printf("setting disabled to %t\n", status.Disabled)
 	common.SetStatus(status)
+	auditAlerting(c, r, !status.Disabled)
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
-func StartWebServer(bind, auth string) error {
+func StartWebServer(bind, auth, probeToken string, oidcConfig OIDCConfig) error {
 	err := loadTemplates()
 	if err != nil {
 		return err
 	}
 
+	goji.Handle("/probe/*", probeMux(probeToken))
+
+	// the public status page is registered before the admin catch-all, so it
+	// skips auth
+	goji.Get("/public", publicStatusRoute)
+	goji.Get("/public.json", publicStatusJSONRoute)
+
+	// the old single --web_auth login becomes the first admin user
 	if auth != "" {
-		authParts := strings.Split(auth, ":")
-		goji.Use(httpauth.SimpleBasicAuth(authParts[0], authParts[1]))
+		parts := strings.SplitN(auth, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("web_auth must be user:pass")
+		}
+		done, err := common.BootstrapAdmin(parts[0], parts[1])
+		if err != nil {
+			return err
+		}
+		if done != "" {
+			log.Println(done)
+		}
+	}
+
+	goji.Get("/login", loginRoute)
+	goji.Post("/login", loginPostRoute)
+	if oidcConfig.Issuer != "" {
+		oidc, err = newOIDCProvider(oidcConfig)
+		if err != nil {
+			return err
+		}
+		goji.Get("/login/oidc", oidcLoginRoute)
+		goji.Get("/login/oidc/callback", oidcCallbackRoute)
 	}
 
-	goji.Get("/", homeRoute)
-	goji.Get("/status", statusRoute)
-	goji.Get("/robots.txt", robotsRoute)
-	goji.Get("/setEnabled", setEnabledRoute)
-	goji.Handle("/config", configRoute)
+	goji.Get(apiPrefix+"/openapi.json", openAPIRoute)
+	goji.Handle(apiPrefix+"/*", apiMux(webAuth(true)))
+
+	admin := web.New()
+	admin.Use(webAuth(false))
+
+	admin.Get("/", homeRoute)
+	admin.Get("/status", statusRoute)
+	admin.Get("/metrics", metricsRoute)
+	admin.Get("/robots.txt", robotsRoute)
+	admin.Get("/setEnabled", setEnabledRoute)
+	admin.Get("/config", configEditorRoute)
+	// older scripts post raw JSON here. since user accounts, form posts with
+	// basic auth need a CSRF token, so scripts must send an admin API token
+	// as "Authorization: Bearer ..." instead
+	admin.Post("/config", configRoute)
+	admin.Handle("/config/raw", configRoute)
+	admin.Get("/config/target", targetFormRoute)
+	admin.Post("/config/target", saveTargetRoute)
+	admin.Post("/config/target/delete", deleteTargetRoute)
+	admin.Post("/config/contacts", saveContactsRoute)
+	admin.Post("/config/settings", saveSettingsRoute)
+	admin.Get("/config/versions", configVersionsRoute)
+	admin.Post("/config/rollback", rollbackConfigRoute)
+	admin.Get("/history", historyRoute)
+	admin.Get("/history.json", historyJSONRoute)
+	admin.Get("/reports", reportsRoute)
+	admin.Get("/reports.csv", reportsCSVRoute)
+	admin.Get("/reports.json", reportsJSONRoute)
+	admin.Get("/slo.json", sloJSONRoute)
+	admin.Post("/maintenance", addMaintenanceRoute)
+	admin.Post("/maintenance/delete", deleteMaintenanceRoute)
+	admin.Get("/incidents", incidentsRoute)
+	admin.Get("/incidents.json", incidentsJSONRoute)
+	admin.Get("/incident", incidentRoute)
+	admin.Get("/incident.json", incidentJSONRoute)
+	admin.Post("/incident/ack", ackIncidentRoute)
+	admin.Post("/incident/note", incidentNoteRoute)
+	admin.Get("/tokens", tokensRoute)
+	admin.Post("/tokens", createTokenRoute)
+	admin.Post("/tokens/revoke", revokeTokenRoute)
+	admin.Post("/logout", logoutRoute)
+	admin.Get("/users", usersRoute)
+	admin.Post("/users", createUserRoute)
+	admin.Post("/users/role", setUserRoleRoute)
+	admin.Post("/users/password", setUserPasswordRoute)
+	admin.Post("/users/delete", deleteUserRoute)
+	admin.Get("/audit", auditRoute)
+	goji.Handle("/*", admin)
 
 	listener, err := net.Listen("tcp", bind)
 	if err != nil {